@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/spf13/pflag"
+	"github.com/sstarcher/newrelic-operator/pkg/export"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+)
+
+// runExport implements `newrelic-operator export`, it writes the objects of the account in
+// NEW_RELIC_APIKEY as resources that adopt them when applied
+func runExport(args []string) error {
+	flags := pflag.NewFlagSet("export", pflag.ExitOnError)
+	namespace := flags.StringP("namespace", "n", "", "namespace to set on the exported resources")
+	accountRef := flags.String("account-ref", "", "NewRelicAccount to reference from the exported resources")
+	nameFilter := flags.String("name-filter", "", "only export objects whose name matches this regular expression")
+	output := flags.StringP("output", "o", "-", "file to write the resources to, - writes to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options := export.Options{
+		Namespace:  *namespace,
+		AccountRef: *accountRef,
+		Warnings:   os.Stderr,
+	}
+
+	if *nameFilter != "" {
+		filter, err := regexp.Compile(*nameFilter)
+		if err != nil {
+			return fmt.Errorf("invalid name filter %w", err)
+		}
+		options.NameFilter = filter
+	}
+
+	client, err := newrelic.NewFromEnv()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	return export.Export(client, out, options)
+}
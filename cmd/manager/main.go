@@ -12,8 +12,11 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
 
+	"github.com/sstarcher/newrelic-operator/pkg/account"
 	"github.com/sstarcher/newrelic-operator/pkg/apis"
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
 	"github.com/sstarcher/newrelic-operator/pkg/controller"
+	"github.com/sstarcher/newrelic-operator/pkg/webhook"
 	"github.com/sstarcher/newrelic-operator/version"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -47,6 +50,14 @@ func printVersion() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Add the zap logger flag set to the CLI. The flag set must
 	// be added before calling pflag.Parse().
 	pflag.CommandLine.AddFlagSet(zap.FlagSet())
@@ -55,6 +66,14 @@ func main() {
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
+	// The validating webhook is served when a port is set, the directory
+	// holds tls.crt and tls.key and is reloaded when they change
+	webhookPort := pflag.Int("webhook-port", 0, "port of the validating webhook server, 0 disables it")
+	webhookCertDir := pflag.String("webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "directory holding the webhook certificate")
+
+	// Synced objects are compared with New Relic again after this interval to repair drift
+	pflag.DurationVar(&newrelicv1alpha1.DriftInterval, "drift-interval", newrelicv1alpha1.DriftInterval, "how often synced objects are checked for changes made in New Relic")
+
 	pflag.Parse()
 
 	// Use a zap logr.Logger implementation. If none of the zap
@@ -94,6 +113,8 @@ func main() {
 	mgr, err := manager.New(cfg, manager.Options{
 		Namespace:          namespace,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metricsHost, metricsPort),
+		Port:               *webhookPort,
+		CertDir:            *webhookCertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -109,11 +130,21 @@ func main() {
 	}
 
 	// Setup all Controllers
-	if err := controller.AddToManager(mgr); err != nil {
+	// One resolver is shared by all controllers and the webhooks so each account has a single cached client
+	accounts := account.NewResolver(mgr.GetClient())
+	if err := controller.AddToManager(mgr, accounts); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	// Setup the validating webhooks
+	if *webhookPort != 0 {
+		if err := webhook.AddToManager(mgr, accounts); err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+	}
+
 	// Add the Metrics Service
 	addMetrics(ctx, cfg, namespace)
 
@@ -6,7 +6,6 @@ require (
 	github.com/cenkalti/backoff v2.1.1+incompatible
 	github.com/go-logr/logr v0.1.0
 	github.com/newrelic/newrelic-client-go v0.23.1
-
 )
 
 require (
@@ -16,6 +15,7 @@ require (
 	k8s.io/apimachinery v0.0.0
 	k8s.io/client-go v12.0.0+incompatible
 	sigs.k8s.io/controller-runtime v0.4.0
+	sigs.k8s.io/yaml v1.1.0
 )
 
 // Pinned to kubernetes-1.16.2
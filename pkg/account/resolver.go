@@ -0,0 +1,158 @@
+// Package account resolves the New Relic credentials used for a resource
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Object is a resource that can reference a NewRelicAccount
+type Object interface {
+	GetNamespace() string
+	GetAccountRef() *corev1.LocalObjectReference
+}
+
+// Resolver finds the New Relic client for a resource.
+// Clients are cached per NewRelicAccount and rebuilt when the credentials in the Secret change.
+type Resolver struct {
+	client   client.Client
+	fallback *newrelic.Client
+	// fallbackCredentials are the credentials of fallback
+	fallbackCredentials newrelic.Credentials
+	// newClient is swapped out in tests
+	newClient func(newrelic.Credentials) (*newrelic.Client, error)
+
+	mu      sync.Mutex
+	clients map[types.NamespacedName]cachedClient
+}
+
+type cachedClient struct {
+	credentials newrelic.Credentials
+	client      *newrelic.Client
+}
+
+// NewResolver returns a Resolver reading accounts through c.
+// When NEW_RELIC_APIKEY is set it is used for namespaces without a default account.
+func NewResolver(c client.Client) *Resolver {
+	r := &Resolver{
+		client:    c,
+		newClient: newrelic.NewFromCredentials,
+		clients:   map[types.NamespacedName]cachedClient{},
+	}
+
+	// An error only means the environment has no API key configured
+	if fallback, err := newrelic.NewFromEnv(); err == nil {
+		r.fallback = fallback
+		r.fallbackCredentials = newrelic.CredentialsFromEnv()
+	}
+	return r
+}
+
+// ClientFor returns the New Relic client for obj.
+// The accountRef of obj is used when set, otherwise the default account of the namespace and finally NEW_RELIC_APIKEY.
+func (r *Resolver) ClientFor(ctx context.Context, obj Object) (*newrelic.Client, error) {
+	key, credentials, err := r.resolve(ctx, obj.GetNamespace(), obj.GetAccountRef())
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return r.fallback, nil
+	}
+	return r.get(*key, credentials)
+}
+
+// CredentialsFor returns the credentials of the account used by resources in namespace with the accountRef ref
+func (r *Resolver) CredentialsFor(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (newrelic.Credentials, error) {
+	_, credentials, err := r.resolve(ctx, namespace, ref)
+	return credentials, err
+}
+
+// resolve returns the NewRelicAccount used in namespace with the accountRef ref and its credentials,
+// the key is nil when NEW_RELIC_APIKEY is used
+func (r *Resolver) resolve(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (*types.NamespacedName, newrelic.Credentials, error) {
+	name := v1alpha1.DefaultAccountName
+	if ref != nil {
+		name = ref.Name
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	account := &v1alpha1.NewRelicAccount{}
+	err := r.client.Get(ctx, key, account)
+	if err != nil {
+		if errors.IsNotFound(err) && ref == nil {
+			if r.fallback == nil {
+				return nil, newrelic.Credentials{}, &v1alpha1.DependencyError{Err: fmt.Errorf("no accountRef set, NewRelicAccount %s does not exist and NEW_RELIC_APIKEY is not configured", key)}
+			}
+			return nil, r.fallbackCredentials, nil
+		}
+		return nil, newrelic.Credentials{}, dependencyError(err)
+	}
+
+	secret := &corev1.Secret{}
+	err = r.client.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: account.Spec.SecretRef.Name}, secret)
+	if err != nil {
+		return nil, newrelic.Credentials{}, dependencyError(err)
+	}
+
+	credentials, err := credentialsFromSecret(secret)
+	if err != nil {
+		return nil, credentials, fmt.Errorf("secret %s/%s %v", secret.Namespace, secret.Name, err)
+	}
+	return &key, credentials, nil
+}
+
+func (r *Resolver) get(key types.NamespacedName, credentials newrelic.Credentials) (*newrelic.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.clients[key]; ok && cached.credentials == credentials {
+		return cached.client, nil
+	}
+
+	c, err := r.newClient(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	r.clients[key] = cachedClient{credentials: credentials, client: c}
+	return c, nil
+}
+
+// dependencyError marks missing accounts and secrets as dependencies that may show up later
+func dependencyError(err error) error {
+	if errors.IsNotFound(err) {
+		return &v1alpha1.DependencyError{Err: err}
+	}
+	return err
+}
+
+func credentialsFromSecret(secret *corev1.Secret) (newrelic.Credentials, error) {
+	credentials := newrelic.Credentials{
+		AdminAPIKey:    string(secret.Data[v1alpha1.AdminAPIKeyKey]),
+		PersonalAPIKey: string(secret.Data[v1alpha1.PersonalAPIKeyKey]),
+		Region:         string(secret.Data[v1alpha1.RegionKey]),
+	}
+
+	if credentials.AdminAPIKey == "" && credentials.PersonalAPIKey == "" {
+		return credentials, fmt.Errorf("requires %s or %s", v1alpha1.AdminAPIKeyKey, v1alpha1.PersonalAPIKeyKey)
+	}
+
+	if value, ok := secret.Data[v1alpha1.AccountIDKey]; ok {
+		id, err := strconv.Atoi(string(value))
+		if err != nil {
+			return credentials, fmt.Errorf("has an invalid %s %v", v1alpha1.AccountIDKey, err)
+		}
+		credentials.AccountID = id
+	}
+
+	return credentials, nil
+}
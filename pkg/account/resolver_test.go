@@ -0,0 +1,158 @@
+package account
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestResolver(t *testing.T, objs ...runtime.Object) (*Resolver, client.Client) {
+	os.Unsetenv("NEW_RELIC_APIKEY")
+
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewFakeClientWithScheme(s, objs...)
+	return NewResolver(c), c
+}
+
+func newAccount(name string, secret string) *v1alpha1.NewRelicAccount {
+	return &v1alpha1.NewRelicAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec: v1alpha1.NewRelicAccountSpec{
+			SecretRef: corev1.LocalObjectReference{Name: secret},
+		},
+	}
+}
+
+func newSecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestClientForAccountRef(t *testing.T) {
+	r, _ := newTestResolver(t,
+		newAccount("eu", "eu-credentials"),
+		newSecret("eu-credentials", map[string]string{
+			v1alpha1.AdminAPIKeyKey: "key",
+			v1alpha1.AccountIDKey:   "1234",
+			v1alpha1.RegionKey:      "EU",
+		}),
+	)
+
+	monitor := &v1alpha1.Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "team"},
+		Spec: v1alpha1.MonitorSpec{
+			AccountRef: &corev1.LocalObjectReference{Name: "eu"},
+		},
+	}
+
+	nrClient, err := r.ClientFor(context.TODO(), monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nrClient.AccountID != 1234 {
+		t.Fatalf("expected account id 1234, got %d", nrClient.AccountID)
+	}
+
+	monitor.Spec.AccountRef.Name = "missing"
+	if _, err = r.ClientFor(context.TODO(), monitor); err == nil {
+		t.Fatal("expected a missing account reference to fail")
+	}
+}
+
+func TestClientForNamespaceDefault(t *testing.T) {
+	r, _ := newTestResolver(t)
+
+	policy := &v1alpha1.AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "team"},
+	}
+
+	if _, err := r.ClientFor(context.TODO(), policy); err == nil {
+		t.Fatal("expected an error without any account configured")
+	}
+
+	r, _ = newTestResolver(t,
+		newAccount(v1alpha1.DefaultAccountName, "credentials"),
+		newSecret("credentials", map[string]string{v1alpha1.PersonalAPIKeyKey: "key"}),
+	)
+	if _, err := r.ClientFor(context.TODO(), policy); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientForSecretRotation(t *testing.T) {
+	r, c := newTestResolver(t,
+		newAccount(v1alpha1.DefaultAccountName, "credentials"),
+		newSecret("credentials", map[string]string{v1alpha1.AdminAPIKeyKey: "old"}),
+	)
+
+	built := []newrelic.Credentials{}
+	r.newClient = func(credentials newrelic.Credentials) (*newrelic.Client, error) {
+		built = append(built, credentials)
+		return &newrelic.Client{}, nil
+	}
+
+	dashboard := &v1alpha1.Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "team"},
+	}
+
+	first, err := r.ClientFor(context.TODO(), dashboard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := r.ClientFor(context.TODO(), dashboard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || len(built) != 1 {
+		t.Fatal("expected the client to be cached")
+	}
+
+	err = c.Update(context.TODO(), newSecret("credentials", map[string]string{v1alpha1.AdminAPIKeyKey: "new"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	third, err := r.ClientFor(context.TODO(), dashboard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third == first || len(built) != 2 || built[1].AdminAPIKey != "new" {
+		t.Fatal("expected the client to be rebuilt after the secret changed")
+	}
+}
+
+func TestCredentialsFromSecret(t *testing.T) {
+	_, err := credentialsFromSecret(newSecret("credentials", map[string]string{}))
+	if err == nil {
+		t.Fatal("expected an error without an api key")
+	}
+
+	_, err = credentialsFromSecret(newSecret("credentials", map[string]string{
+		v1alpha1.AdminAPIKeyKey: "key",
+		v1alpha1.AccountIDKey:   "abc",
+	}))
+	if err == nil {
+		t.Fatal("expected an error for an invalid account id")
+	}
+}
@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReasonAdopted is recorded when an existing New Relic object is taken over
+const ReasonAdopted = "Adopted"
+
+// parseImportID converts the importID of kinds that use numeric IDs
+func parseImportID(id string) (int, error) {
+	value, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, invalidSpec(fmt.Errorf("importID %q is not a number", id))
+	}
+	return value, nil
+}
+
+// importNotFound is returned when the object referred to by importID does not exist
+func importNotFound(id string) error {
+	return missingDependency(fmt.Errorf("unable to find object %s to import", id))
+}
+
+// ambiguousName is returned when adoptExisting matches more than one object
+func ambiguousName(name string, count int) error {
+	return invalidSpec(fmt.Errorf("found %d objects named %s, set importID to choose one", count, name))
+}
+
+// adopted records the ID of the existing object, the next update reconciles it with the spec
+func (s *Status) adopted(ctx context.Context, id string) {
+	message := fmt.Sprintf("adopted existing object %s", id)
+	GetLogger(ctx).Info(message)
+	recordEvent(ctx, corev1.EventTypeNormal, ReasonAdopted, message)
+	s.ID = &id
+	s.Hash = nil
+}
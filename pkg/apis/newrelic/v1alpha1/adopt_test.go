@@ -0,0 +1,166 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdoptExistingPolicy(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	existing, err := account.CreatePolicy(alerts.Policy{
+		Name:               "policy",
+		IncidentPreference: alerts.IncidentPreferenceTypes.PerPolicy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = account.CreatePolicy(alerts.Policy{Name: "policy-other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: AlertPolicySpec{
+			AdoptExisting:      true,
+			IncidentPreference: string(alerts.IncidentPreferenceTypes.PerCondition),
+		},
+	}
+
+	if DoReconcile(ctx, L, policy).Requeue {
+		t.Fatalf("unexpected requeue: %s", policy.Status.Info)
+	}
+	if *policy.Status.GetID() != existing.ID {
+		t.Fatalf("expected policy %d to be adopted, got %s", existing.ID, *policy.Status.ID)
+	}
+	if !hasFinalizer(policy) {
+		t.Fatal("expected finalizer to be set on adoption")
+	}
+
+	policies, err := account.ListPolicies(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected no new policy to be created, got %v", policies)
+	}
+
+	data, err := account.GetPolicy(existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.IncidentPreference != alerts.IncidentPreferenceTypes.PerCondition {
+		t.Fatalf("expected adopted policy to be updated, got %s", data.IncidentPreference)
+	}
+}
+
+func TestAdoptExistingPolicyName(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	existing, err := account.CreatePolicy(alerts.Policy{Name: "Hand Made Policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hand-made-policy"},
+		Spec:       AlertPolicySpec{Name: "Hand Made Policy", AdoptExisting: true},
+	}
+
+	if DoReconcile(ctx, L, policy).Requeue {
+		t.Fatalf("unexpected requeue: %s", policy.Status.Info)
+	}
+	if *policy.Status.GetID() != existing.ID {
+		t.Fatalf("expected policy %d to be adopted by spec.name, got %v", existing.ID, policy.Status.ID)
+	}
+
+	data, err := account.GetPolicy(existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Name != "Hand Made Policy" {
+		t.Fatalf("expected the New Relic name to be kept, got %s", data.Name)
+	}
+}
+
+func TestAdoptAmbiguousName(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	for i := 0; i < 2; i++ {
+		if _, err := account.CreatePolicy(alerts.Policy{Name: "policy"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec:       AlertPolicySpec{AdoptExisting: true},
+	}
+
+	if !DoReconcile(ctx, L, policy).Requeue {
+		t.Fatal("expected adoption of an ambiguous name to fail")
+	}
+	if policy.IsCreated() {
+		t.Fatal("expected no policy to be adopted")
+	}
+	if condition := policy.Status.GetCondition(ConditionReady); condition == nil || condition.Reason != ReasonInvalidSpec {
+		t.Fatalf("expected invalid spec, got %+v", condition)
+	}
+}
+
+func TestAdoptMonitorImportID(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	existing, err := account.CreateMonitor(synthetics.Monitor{
+		Name:      "hand-made",
+		Type:      synthetics.MonitorTypes.Ping,
+		Frequency: 10,
+		URI:       "https://example.com",
+		Locations: []string{"AWS_US_WEST_1"},
+		Status:    synthetics.MonitorStatus.Enabled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri := "https://example.com"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec:       MonitorSpec{URI: &uri, ImportID: &existing.ID},
+	}
+	monitor.Default()
+
+	if DoReconcile(ctx, L, monitor).Requeue {
+		t.Fatalf("unexpected requeue: %s", monitor.Status.Info)
+	}
+	if *monitor.Status.ID != existing.ID {
+		t.Fatalf("expected monitor %s to be adopted, got %s", existing.ID, *monitor.Status.ID)
+	}
+
+	data, err := account.GetMonitor(existing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Name != "monitor" {
+		t.Fatalf("expected adopted monitor to be renamed, got %s", data.Name)
+	}
+
+	missing := "missing"
+	monitor = &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec:       MonitorSpec{URI: &uri, ImportID: &missing},
+	}
+	monitor.Default()
+	if !DoReconcile(ctx, L, monitor).Requeue {
+		t.Fatal("expected import of a missing monitor to fail")
+	}
+}
@@ -0,0 +1,437 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type data map[string]ConfigurationValue
+
+// ConfigurationValue is a channel setting given inline, or read from a Secret to keep credentials out of the resource.
+// It is written as a plain string, or as an object with value or valueFrom.secretKeyRef, so the schema leaves its type open.
+// +kubebuilder:validation:Type=""
+// +kubebuilder:validation:XPreserveUnknownFields
+type ConfigurationValue struct {
+	Value     string                    `json:"value,omitempty"`
+	ValueFrom *ConfigurationValueSource `json:"valueFrom,omitempty"`
+}
+
+// ConfigurationValueSource selects where a channel setting is read from
+type ConfigurationValueSource struct {
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// UnmarshalJSON accepts a plain string as an inline value
+func (v *ConfigurationValue) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		*v = ConfigurationValue{}
+		return json.Unmarshal(b, &v.Value)
+	}
+
+	type plain ConfigurationValue
+	return json.Unmarshal(b, (*plain)(v))
+}
+
+// MarshalJSON writes inline values as plain strings
+func (v ConfigurationValue) MarshalJSON() ([]byte, error) {
+	if v.ValueFrom == nil {
+		return json.Marshal(v.Value)
+	}
+
+	type plain ConfigurationValue
+	return json.Marshal(plain(v))
+}
+
+// EmailChannel sends notifications to email addresses
+type EmailChannel struct {
+	// +kubebuilder:validation:MinItems=1
+	Recipients            []string `json:"recipients"`
+	IncludeJSONAttachment bool     `json:"includeJSONAttachment,omitempty"`
+}
+
+// SlackChannel posts notifications to a Slack incoming webhook
+type SlackChannel struct {
+	URL ConfigurationValue `json:"url"`
+	// Channel overrides the channel of the webhook
+	Channel string `json:"channel,omitempty"`
+}
+
+// PagerDutyChannel triggers incidents on a PagerDuty service
+type PagerDutyChannel struct {
+	ServiceKey ConfigurationValue `json:"serviceKey"`
+}
+
+// OpsGenieChannel creates OpsGenie alerts
+type OpsGenieChannel struct {
+	APIKey     ConfigurationValue `json:"apiKey"`
+	Teams      []string           `json:"teams,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Recipients []string           `json:"recipients,omitempty"`
+	// Region defaults to US
+	// +kubebuilder:validation:Enum=US;EU
+	Region string `json:"region,omitempty"`
+}
+
+// VictorOpsChannel sends notifications to a VictorOps routing key
+type VictorOpsChannel struct {
+	Key      ConfigurationValue `json:"key"`
+	RouteKey string             `json:"routeKey"`
+}
+
+// WebhookChannel posts notifications to a URL
+type WebhookChannel struct {
+	BaseURL      string              `json:"baseURL"`
+	AuthUsername string              `json:"authUsername,omitempty"`
+	AuthPassword *ConfigurationValue `json:"authPassword,omitempty"`
+	// PayloadType defaults to application/json
+	// +kubebuilder:validation:Enum=application/json;application/x-www-form-urlencoded
+	PayloadType string `json:"payloadType,omitempty"`
+	// Payload replaces the default payload of New Relic
+	Payload map[string]string `json:"payload,omitempty"`
+	// Headers are added to each request, values can be read from Secrets
+	Headers map[string]ConfigurationValue `json:"headers,omitempty"`
+}
+
+// UserChannel notifies a New Relic user
+type UserChannel struct {
+	UserID string `json:"userID"`
+}
+
+// channelType returns the type of the typed configuration that is set, or an empty type when none is set
+func (s *AlertChannelSpec) channelType() (alerts.ChannelType, error) {
+	set := []alerts.ChannelType{}
+	if s.Email != nil {
+		set = append(set, alerts.ChannelTypes.Email)
+	}
+	if s.Slack != nil {
+		set = append(set, alerts.ChannelTypes.Slack)
+	}
+	if s.PagerDuty != nil {
+		set = append(set, alerts.ChannelTypes.PagerDuty)
+	}
+	if s.OpsGenie != nil {
+		set = append(set, alerts.ChannelTypes.OpsGenie)
+	}
+	if s.VictorOps != nil {
+		set = append(set, alerts.ChannelTypes.VictorOps)
+	}
+	if s.Webhook != nil {
+		set = append(set, alerts.ChannelTypes.Webhook)
+	}
+	if s.User != nil {
+		set = append(set, alerts.ChannelTypes.User)
+	}
+
+	switch {
+	case len(set) > 1:
+		return "", fmt.Errorf("only one channel configuration can be set, found %d", len(set))
+	case len(set) == 0:
+		return "", nil
+	case s.Configuration != nil:
+		return "", fmt.Errorf("configuration can not be combined with %s", set[0])
+	case s.Type != "" && alerts.ChannelType(s.Type) != set[0]:
+		return "", fmt.Errorf("type %s does not match the %s configuration", s.Type, set[0])
+	}
+	return set[0], nil
+}
+
+// values returns every setting that can be read from a Secret
+func (s *AlertChannelSpec) values() []ConfigurationValue {
+	values := []ConfigurationValue{}
+	for _, value := range s.Configuration {
+		values = append(values, value)
+	}
+	if s.Slack != nil {
+		values = append(values, s.Slack.URL)
+	}
+	if s.PagerDuty != nil {
+		values = append(values, s.PagerDuty.ServiceKey)
+	}
+	if s.OpsGenie != nil {
+		values = append(values, s.OpsGenie.APIKey)
+	}
+	if s.VictorOps != nil {
+		values = append(values, s.VictorOps.Key)
+	}
+	if s.Webhook != nil {
+		if s.Webhook.AuthPassword != nil {
+			values = append(values, *s.Webhook.AuthPassword)
+		}
+		for _, value := range s.Webhook.Headers {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// UsesSecret reports if a configuration value is read from the Secret
+func (s *AlertChannel) UsesSecret(name string) bool {
+	for _, value := range s.Spec.values() {
+		if value.ValueFrom != nil && value.ValueFrom.SecretKeyRef != nil && value.ValueFrom.SecretKeyRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// valueResolver reads configuration values, remembering the ones read from Secrets so they can be kept out of errors
+type valueResolver struct {
+	ctx       context.Context
+	namespace string
+	secrets   []string
+	// versions are the Secret keys read and the resource versions they were read at
+	versions []string
+	// offline leaves Secrets unread, values read from them are taken to be set
+	offline bool
+}
+
+// secretPlaceholder stands in for values read from Secrets by an offline resolver
+const secretPlaceholder = "<secret>"
+
+func (r *valueResolver) resolve(name string, value ConfigurationValue) (string, error) {
+	if value.ValueFrom == nil {
+		return value.Value, nil
+	}
+	if value.Value != "" || value.ValueFrom.SecretKeyRef == nil {
+		return "", invalidSpec(fmt.Errorf("%s requires either value or valueFrom.secretKeyRef", name))
+	}
+	if r.offline {
+		return secretPlaceholder, nil
+	}
+
+	ref := value.ValueFrom.SecretKeyRef
+	secret, version, err := readSecretKeyVersion(r.ctx, r.namespace, ref)
+	if err != nil {
+		return "", err
+	}
+	r.secrets = append(r.secrets, secret)
+	r.versions = append(r.versions, ref.Name+"/"+ref.Key+"@"+version)
+	return secret, nil
+}
+
+// required resolves a value that must not be empty
+func (r *valueResolver) required(name string, value ConfigurationValue) (string, error) {
+	result, err := r.resolve(name, value)
+	if err == nil && result == "" {
+		err = invalidSpec(fmt.Errorf("%s is required", name))
+	}
+	return result, err
+}
+
+// toNewRelic builds the channel from the spec, it also returns the resolver that read the values from Secrets
+func (s *AlertChannel) toNewRelic(ctx context.Context) (*alerts.Channel, *valueResolver, error) {
+	resolver := &valueResolver{ctx: ctx, namespace: s.GetNamespace()}
+	data, _, err := s.build(resolver)
+	return data, resolver, err
+}
+
+// validate checks the spec without calling New Relic or reading Secrets
+func (s *AlertChannel) validate() error {
+	_, _, err := s.build(&valueResolver{offline: true})
+	return err
+}
+
+// build converts the spec with values read through resolver
+func (s *AlertChannel) build(resolver *valueResolver) (*alerts.Channel, []string, error) {
+	for _, ref := range s.Spec.PolicyRefs {
+		if ref.Name == "" {
+			return nil, nil, invalidSpec(errors.New("references require a name"))
+		}
+	}
+
+	channelType, err := s.Spec.channelType()
+	if err != nil {
+		return nil, nil, invalidSpec(err)
+	}
+
+	var configuration *alerts.ChannelConfiguration
+	if channelType == "" {
+		channelType = alerts.ChannelType(s.Spec.Type)
+		configuration, err = s.untypedConfiguration(resolver)
+	} else {
+		configuration, err = s.typedConfiguration(resolver)
+	}
+	if err != nil {
+		return nil, resolver.secrets, err
+	}
+
+	data := alerts.Channel{
+		Name:          s.newRelicName(),
+		Type:          channelType,
+		Configuration: *configuration,
+	}
+	if s.Status.ID != nil {
+		data.ID = int(*s.Status.GetID())
+	}
+	return &data, resolver.secrets, nil
+}
+
+// untypedConfiguration maps the configuration keys directly onto the New Relic settings
+func (s *AlertChannel) untypedConfiguration(resolver *valueResolver) (*alerts.ChannelConfiguration, error) {
+	configuration := map[string]string{}
+	for key, value := range s.Spec.Configuration {
+		result, err := resolver.resolve("configuration "+key, value)
+		if err != nil {
+			return nil, err
+		}
+		configuration[key] = result
+	}
+
+	switch alerts.ChannelType(s.Spec.Type) {
+	case "":
+		return nil, invalidSpec(errors.New("no valid type specified"))
+	case alerts.ChannelTypes.Slack:
+		if _, ok := configuration["channel"]; !ok {
+			return nil, invalidSpec(errors.New("slack notifications require channel configuration"))
+		}
+		if _, ok := configuration["url"]; !ok {
+			return nil, invalidSpec(errors.New("slack notifications require url configuration"))
+		}
+	}
+
+	raw, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &alerts.ChannelConfiguration{}
+	err = json.Unmarshal(raw, data)
+	if err != nil {
+		return nil, invalidSpec(fmt.Errorf("invalid configuration: %w", err))
+	}
+	return data, nil
+}
+
+// typedConfiguration validates the typed configuration and converts it into the New Relic settings
+func (s *AlertChannel) typedConfiguration(resolver *valueResolver) (*alerts.ChannelConfiguration, error) {
+	data := &alerts.ChannelConfiguration{}
+	var err error
+
+	switch {
+	case s.Spec.Email != nil:
+		if len(s.Spec.Email.Recipients) == 0 {
+			return nil, invalidSpec(errors.New("email.recipients is required"))
+		}
+		data.Recipients = strings.Join(s.Spec.Email.Recipients, ",")
+		data.IncludeJSONAttachment = strconv.FormatBool(s.Spec.Email.IncludeJSONAttachment)
+
+	case s.Spec.Slack != nil:
+		data.URL, err = resolver.required("slack.url", s.Spec.Slack.URL)
+		data.Channel = s.Spec.Slack.Channel
+
+	case s.Spec.PagerDuty != nil:
+		data.ServiceKey, err = resolver.required("pagerDuty.serviceKey", s.Spec.PagerDuty.ServiceKey)
+
+	case s.Spec.OpsGenie != nil:
+		data.APIKey, err = resolver.required("opsGenie.apiKey", s.Spec.OpsGenie.APIKey)
+		data.Teams = strings.Join(s.Spec.OpsGenie.Teams, ",")
+		data.Tags = strings.Join(s.Spec.OpsGenie.Tags, ",")
+		data.Recipients = strings.Join(s.Spec.OpsGenie.Recipients, ",")
+		data.Region = s.Spec.OpsGenie.Region
+		if data.Region == "" {
+			data.Region = "US"
+		}
+
+	case s.Spec.VictorOps != nil:
+		if s.Spec.VictorOps.RouteKey == "" {
+			return nil, invalidSpec(errors.New("victorOps.routeKey is required"))
+		}
+		data.Key, err = resolver.required("victorOps.key", s.Spec.VictorOps.Key)
+		data.RouteKey = s.Spec.VictorOps.RouteKey
+
+	case s.Spec.Webhook != nil:
+		err = webhookConfiguration(s.Spec.Webhook, resolver, data)
+
+	case s.Spec.User != nil:
+		if s.Spec.User.UserID == "" {
+			return nil, invalidSpec(errors.New("user.userID is required"))
+		}
+		data.UserID = s.Spec.User.UserID
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func webhookConfiguration(webhook *WebhookChannel, resolver *valueResolver, data *alerts.ChannelConfiguration) error {
+	if webhook.BaseURL == "" {
+		return invalidSpec(errors.New("webhook.baseURL is required"))
+	}
+	data.BaseURL = webhook.BaseURL
+	data.AuthUsername = webhook.AuthUsername
+
+	if webhook.AuthPassword != nil {
+		password, err := resolver.resolve("webhook.authPassword", *webhook.AuthPassword)
+		if err != nil {
+			return err
+		}
+		data.AuthPassword = password
+	}
+
+	if len(webhook.Payload) > 0 {
+		data.PayloadType = webhook.PayloadType
+		if data.PayloadType == "" {
+			data.PayloadType = "application/json"
+		}
+
+		payload := map[string]interface{}{}
+		for key, value := range webhook.Payload {
+			payload[key] = value
+		}
+		data.Payload = payload
+	} else if webhook.PayloadType != "" {
+		return invalidSpec(errors.New("webhook.payloadType requires webhook.payload"))
+	}
+
+	if len(webhook.Headers) > 0 {
+		headers := map[string]interface{}{}
+		for key, value := range webhook.Headers {
+			header, err := resolver.resolve("webhook.headers "+key, value)
+			if err != nil {
+				return err
+			}
+			headers[key] = header
+		}
+		data.Headers = headers
+	}
+	return nil
+}
+
+// configurationHash is the digest of the settings the channel is created with. Values read from Secrets are left out
+// so the status never carries a digest of a credential, the resource versions they were read at make it change when
+// a Secret is rotated instead.
+func (s *AlertChannel) configurationHash(resolver *valueResolver) ([]byte, error) {
+	input, _, err := s.build(&valueResolver{offline: true})
+	if err != nil {
+		return nil, err
+	}
+	input.ID = 0
+	return hashSpec([]interface{}{input, resolver.versions})
+}
+
+// redact hides the secret values in an error before it ends up in the status or logs
+func redact(err error, secrets []string) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	for _, secret := range secrets {
+		if secret != "" {
+			message = strings.ReplaceAll(message, secret, "[redacted]")
+		}
+	}
+	if message == err.Error() {
+		return err
+	}
+	return errors.New(message)
+}
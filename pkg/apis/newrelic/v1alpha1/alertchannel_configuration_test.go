@@ -0,0 +1,141 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigurationValueJSON(t *testing.T) {
+	configuration := data{}
+	err := json.Unmarshal([]byte(`{"channel":"#alerts","url":{"valueFrom":{"secretKeyRef":{"name":"slack","key":"url"}}}}`), &configuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configuration["channel"].Value != "#alerts" || configuration["url"].ValueFrom.SecretKeyRef.Key != "url" {
+		t.Fatalf("unexpected configuration %+v", configuration)
+	}
+
+	raw, err := json.Marshal(data{"channel": {Value: "#alerts"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"channel":"#alerts"}` {
+		t.Fatalf("expected inline values to be written as strings, got %s", raw)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	err := redact(errors.New("invalid url https://hooks.slack.com/secret"), []string{"https://hooks.slack.com/secret"})
+	if strings.Contains(err.Error(), "hooks.slack.com") {
+		t.Fatalf("expected secret to be redacted, got %s", err)
+	}
+}
+
+func TestAlertChannelConfigurationHash(t *testing.T) {
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack"},
+		Spec: AlertChannelSpec{Slack: &SlackChannel{URL: ConfigurationValue{ValueFrom: &ConfigurationValueSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "url"},
+		}}}},
+	}
+
+	hash := func(secret, version string) []byte {
+		resolver := &valueResolver{secrets: []string{secret}, versions: []string{"slack/url@" + version}}
+		result, err := channel.configurationHash(resolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// the digest must not depend on the value, or a weak one could be guessed from the status
+	if string(hash("https://hooks.slack.com/first", "1")) != string(hash("https://hooks.slack.com/second", "1")) {
+		t.Fatal("expected the Secret value to be left out of the hash")
+	}
+	if string(hash("https://hooks.slack.com/first", "1")) == string(hash("https://hooks.slack.com/first", "2")) {
+		t.Fatal("expected a new Secret version to change the hash")
+	}
+}
+
+func TestAlertChannelTypedConfiguration(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     AlertChannelSpec
+		expected alerts.ChannelConfiguration
+	}{
+		{
+			name:     "email",
+			spec:     AlertChannelSpec{Email: &EmailChannel{Recipients: []string{"a@example.com", "b@example.com"}, IncludeJSONAttachment: true}},
+			expected: alerts.ChannelConfiguration{Recipients: "a@example.com,b@example.com", IncludeJSONAttachment: "true"},
+		},
+		{
+			name:     "slack",
+			spec:     AlertChannelSpec{Slack: &SlackChannel{URL: ConfigurationValue{Value: "https://hooks.slack.com/x"}, Channel: "#alerts"}},
+			expected: alerts.ChannelConfiguration{URL: "https://hooks.slack.com/x", Channel: "#alerts"},
+		},
+		{
+			name:     "opsgenie",
+			spec:     AlertChannelSpec{OpsGenie: &OpsGenieChannel{APIKey: ConfigurationValue{Value: "key"}, Teams: []string{"a", "b"}}},
+			expected: alerts.ChannelConfiguration{APIKey: "key", Teams: "a,b", Region: "US"},
+		},
+		{
+			name: "webhook",
+			spec: AlertChannelSpec{Webhook: &WebhookChannel{
+				BaseURL: "https://example.com/hook",
+				Payload: map[string]string{"account": "$ACCOUNT_ID"},
+				Headers: map[string]ConfigurationValue{"X-Token": {Value: "token"}},
+			}},
+			expected: alerts.ChannelConfiguration{
+				BaseURL:     "https://example.com/hook",
+				PayloadType: "application/json",
+				Payload:     map[string]interface{}{"account": "$ACCOUNT_ID"},
+				Headers:     map[string]interface{}{"X-Token": "token"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		channel := &AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: test.name}, Spec: test.spec}
+		data, _, err := channel.toNewRelic(context.TODO())
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(data.Type) != test.name {
+			t.Fatalf("%s: expected the type to be inferred, got %s", test.name, data.Type)
+		}
+
+		expected, _ := json.Marshal(test.expected)
+		actual, _ := json.Marshal(data.Configuration)
+		if string(expected) != string(actual) {
+			t.Fatalf("%s: expected %s, got %s", test.name, expected, actual)
+		}
+	}
+}
+
+func TestAlertChannelTypedValidation(t *testing.T) {
+	tests := map[string]AlertChannelSpec{
+		"two configurations": {Email: &EmailChannel{Recipients: []string{"a@example.com"}}, User: &UserChannel{UserID: "1"}},
+		"mismatched type":    {Type: "slack", Email: &EmailChannel{Recipients: []string{"a@example.com"}}},
+		"untyped and typed":  {Configuration: data{"recipients": {Value: "a@example.com"}}, Email: &EmailChannel{Recipients: []string{"a@example.com"}}},
+		"no recipients":      {Email: &EmailChannel{}},
+		"no slack url":       {Slack: &SlackChannel{Channel: "#alerts"}},
+		"no route key":       {VictorOps: &VictorOpsChannel{Key: ConfigurationValue{Value: "key"}}},
+		"payload type alone": {Webhook: &WebhookChannel{BaseURL: "https://example.com", PayloadType: "application/json"}},
+	}
+
+	for name, spec := range tests {
+		channel := &AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "channel"}, Spec: spec}
+		_, _, err := channel.toNewRelic(context.TODO())
+		var validation *ValidationError
+		if !errors.As(err, &validation) {
+			t.Fatalf("%s: expected a validation error, got %v", name, err)
+		}
+	}
+}
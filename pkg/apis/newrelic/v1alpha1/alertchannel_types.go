@@ -1,20 +1,43 @@
 package v1alpha1
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
+	"fmt"
+	"strconv"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // AlertChannelSpec defines the desired state of AlertChannel
 type AlertChannelSpec struct {
-	// TODO don't require setting of the type
-	Type          string   `json:"type,omitempty"`
-	Configuration data     `json:"configuration,omitempty"`
-	Policies      []string `json:"policies,omitempty"`
+	// Name of the New Relic channel, defaults to the name of the resource
+	Name string `json:"name,omitempty"`
+	// Type is inferred from the typed configuration that is set, it is only required with configuration
+	// +kubebuilder:validation:Enum=email;opsgenie;pagerduty;slack;user;victorops;webhook
+	Type string `json:"type,omitempty"`
+	// Configuration is the untyped channel configuration, the typed configurations are preferred
+	Configuration data              `json:"configuration,omitempty"`
+	Email         *EmailChannel     `json:"email,omitempty"`
+	Slack         *SlackChannel     `json:"slack,omitempty"`
+	PagerDuty     *PagerDutyChannel `json:"pagerDuty,omitempty"`
+	OpsGenie      *OpsGenieChannel  `json:"opsGenie,omitempty"`
+	VictorOps     *VictorOpsChannel `json:"victorOps,omitempty"`
+	Webhook       *WebhookChannel   `json:"webhook,omitempty"`
+	User          *UserChannel      `json:"user,omitempty"`
+	// Policies are the names of New Relic policies the channel is attached to
+	Policies []string `json:"policies,omitempty"`
+	// PolicyRefs are AlertPolicy resources the channel is attached to
+	PolicyRefs []ObjectReference            `json:"policyRefs,omitempty"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -22,11 +45,31 @@ type AlertChannelSpec struct {
 // AlertChannel is the Schema for the alertchannels API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=alertchannels,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type AlertChannel struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              AlertChannelSpec `json:"spec"`
-	Status            Status           `json:"status,omitempty"`
+	Spec              AlertChannelSpec   `json:"spec"`
+	Status            AlertChannelStatus `json:"status,omitempty"`
+}
+
+// AlertChannelStatus is the shared status with the policies and configuration an AlertChannel was synced with
+type AlertChannelStatus struct {
+	Status `json:",inline"`
+	// Policies are the alert policies the channel attached itself to
+	Policies []PolicyStatus `json:"policies,omitempty"`
+	// ConfigurationHash is the digest of the configuration the channel was last synced with, it covers the versions
+	// of the Secrets values were read from rather than the values
+	ConfigurationHash []byte `json:"configurationHash,omitempty"`
+}
+
+// PolicyStatus is an alert policy an AlertChannel is attached to
+type PolicyStatus struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -46,64 +89,114 @@ func init() {
 
 var _ CRD = &AlertChannel{}
 
-type data map[string]string
+// ReasonReplaced is the event reason for a channel that was replaced to apply a new configuration
+const ReasonReplaced = "Replaced"
 
 // IsCreated specifies if the object has been created in new relic yet
 func (s *AlertChannel) IsCreated() bool {
 	return s.Status.IsCreated()
 }
 
-func (s *AlertChannel) toNewRelic() (*alerts.Channel, error) {
-	data := alerts.Channel{
-		Name: s.GetObjectMeta().GetName(),
-		Type: alerts.ChannelType(s.Spec.Type),
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *AlertChannel) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// newRelicName returns the name of the New Relic channel
+func (s *AlertChannel) newRelicName() string {
+	if s.Spec.Name != "" {
+		return s.Spec.Name
 	}
+	return s.GetName()
+}
 
-	bytes, err := json.Marshal(s.Spec.Configuration)
-	if err != nil {
-		return nil, err
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *AlertChannel) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *AlertChannel) GetStatus() *Status {
+	return &s.Status.Status
+}
+
+// Adopt takes over an existing channel matching importID, or matching the name when adoptExisting is set
+func (s *AlertChannel) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Alerts.GetChannel(id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
 	}
 
-	err = json.Unmarshal(bytes, &data.Configuration)
-	if err != nil {
-		return nil, err
+	if !s.Spec.AdoptExisting {
+		return false
 	}
 
-	if s.Status.ID != nil {
-		data.ID = int(*s.Status.GetID())
+	items, err := GetClient(ctx).Alerts.ListChannels()
+	if s.Status.HandleOnError(ctx, err) {
+		return true
 	}
 
-	switch data.Type {
-	// TODO more validation
-	case "":
-		return nil, errors.New("no valid type specified")
-	case alerts.ChannelTypes.Slack:
-		if _, ok := s.Spec.Configuration["channel"]; !ok {
-			return nil, errors.New("slack notifications require channel configuration")
-		}
-		if _, ok := s.Spec.Configuration["url"]; !ok {
-			return nil, errors.New("slack notifications require url configuration")
+	ids := []int{}
+	for _, item := range items {
+		if item.Name == s.newRelicName() {
+			ids = append(ids, item.ID)
 		}
 	}
 
-	return &data, nil
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.newRelicName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
 }
 
 // Create in newrelic
 func (s *AlertChannel) Create(ctx context.Context) bool {
-	input, err := s.toNewRelic()
+	input, resolver, err := s.toNewRelic(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	data, err := client.Alerts.CreateChannel(*input)
+	configHash, err := s.configurationHash(resolver)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
+	data, err := GetClient(ctx).Alerts.CreateChannel(*input)
+	if s.Status.HandleOnError(ctx, redact(err, resolver.secrets)) {
+		return true
+	}
+
 	s.Status.Info = "Created"
 	s.Status.SetID(data.ID)
-	s.SetFinalizers([]string{finalizer})
+	s.Status.ConfigurationHash = configHash
+
+	_, err = s.attachPolicies(ctx, data)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	s.Status.Hash = hash
 	return false
 }
 
@@ -116,7 +209,7 @@ func (s *AlertChannel) Delete(ctx context.Context) bool {
 		return false
 	}
 
-	_, err := client.Alerts.DeleteChannel(int(*id))
+	_, err := GetClient(ctx).Alerts.DeleteChannel(int(*id))
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
@@ -126,6 +219,207 @@ func (s *AlertChannel) Delete(ctx context.Context) bool {
 
 // Update object in newrelic
 func (s *AlertChannel) Update(ctx context.Context) bool {
-	// API does not list this as being updatable
+	id := s.Status.GetID()
+	if id == nil {
+		return s.Create(ctx)
+	}
+
+	input, resolver, err := s.toNewRelic(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	configHash, err := s.configurationHash(resolver)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	current, err := GetClient(ctx).Alerts.GetChannel(int(*id))
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if s.needsReplacement(current, input, configHash) {
+		current, err = s.replace(ctx, current, *input)
+		if s.Status.HandleOnError(ctx, redact(err, resolver.secrets)) {
+			return true
+		}
+	}
+	s.Status.ConfigurationHash = configHash
+
+	changed, err := s.attachPolicies(ctx, current)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if changed && !s.Status.specChanged(hash) {
+		s.Status.markDrift(ctx, drift{"policies"})
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
 	return false
 }
+
+// needsReplacement reports if the channel differs from input, the API can not update channels so they are replaced.
+// Only the name and type can be compared for channels without a configuration hash, as New Relic hides secret settings.
+func (s *AlertChannel) needsReplacement(current *alerts.Channel, input *alerts.Channel, configHash []byte) bool {
+	if s.Status.ConfigurationHash == nil {
+		return current.Name != input.Name || current.Type != input.Type
+	}
+	return !bytes.Equal(s.Status.ConfigurationHash, configHash)
+}
+
+// replace creates a channel from input, attaches it to every policy of the current channel and deletes the current
+// channel, the replacement is removed again when any step fails so no channel is left behind
+func (s *AlertChannel) replace(ctx context.Context, current *alerts.Channel, input alerts.Channel) (*alerts.Channel, error) {
+	input.ID = 0
+	data, err := GetClient(ctx).Alerts.CreateChannel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := func(err error) (*alerts.Channel, error) {
+		_, deleteErr := GetClient(ctx).Alerts.DeleteChannel(data.ID)
+		if deleteErr != nil && !isNotFound(deleteErr) {
+			GetLogger(ctx).Error(deleteErr, "unable to delete replacement channel", "channel", data.ID)
+		}
+		return nil, err
+	}
+
+	data.Links.PolicyIDs = nil
+	for _, policyID := range current.Links.PolicyIDs {
+		_, err = GetClient(ctx).Alerts.UpdatePolicyChannels(policyID, []int{data.ID})
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return rollback(err)
+		}
+		data.Links.PolicyIDs = append(data.Links.PolicyIDs, policyID)
+	}
+
+	_, err = GetClient(ctx).Alerts.DeleteChannel(current.ID)
+	if err != nil && !isNotFound(err) {
+		return rollback(err)
+	}
+
+	message := fmt.Sprintf("replaced channel %d with %d to apply its configuration", current.ID, data.ID)
+	GetLogger(ctx).Info(message)
+	recordEvent(ctx, corev1.EventTypeNormal, ReasonReplaced, message)
+
+	s.Status.SetID(data.ID)
+	return data, nil
+}
+
+// attachPolicies attaches the channel to the policies of the spec and detaches it from the policies it was
+// attached to before that were removed from the spec, it reports if a policy had to be attached
+func (s *AlertChannel) attachPolicies(ctx context.Context, channel *alerts.Channel) (bool, error) {
+	changed := false
+	desired := map[int]bool{}
+	var result []PolicyStatus
+
+	wanted := []PolicyStatus{}
+	for _, name := range s.Spec.Policies {
+		policyID, err := findPolicyID(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		wanted = append(wanted, PolicyStatus{Name: name, ID: *policyID})
+	}
+	for _, ref := range s.Spec.PolicyRefs {
+		policy := &AlertPolicy{}
+		policyID, err := resolveID(ctx, s, "AlertPolicy", ref, policy)
+		if err != nil {
+			return false, err
+		}
+		wanted = append(wanted, PolicyStatus{Name: policy.newRelicName(), ID: policyID})
+	}
+
+	for _, item := range wanted {
+		if desired[item.ID] {
+			continue
+		}
+		desired[item.ID] = true
+
+		if !containsInt(channel.Links.PolicyIDs, item.ID) {
+			_, err := GetClient(ctx).Alerts.UpdatePolicyChannels(item.ID, []int{channel.ID})
+			if err != nil {
+				return false, err
+			}
+			changed = true
+		}
+		result = append(result, item)
+	}
+
+	for _, item := range s.Status.Policies {
+		if desired[item.ID] {
+			continue
+		}
+
+		linked, err := s.linkedByPolicy(ctx, item.ID)
+		if err != nil {
+			return false, err
+		}
+		if linked {
+			// the AlertPolicy would attach the channel again on its next sync
+			GetLogger(ctx).Info("keeping channel attached to a policy that lists it", "policy", item.Name)
+			continue
+		}
+
+		GetLogger(ctx).Info("detaching channel", "policy", item.Name)
+		_, err = GetClient(ctx).Alerts.DeletePolicyChannel(item.ID, channel.ID)
+		if err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+
+	s.Status.Policies = result
+	return changed, nil
+}
+
+// UsesAlertPolicy reports if the channel refers to the AlertPolicy resource namespace/name
+func (s *AlertChannel) UsesAlertPolicy(namespace string, name string) bool {
+	for _, ref := range s.Spec.PolicyRefs {
+		if ref.refersTo(s.GetNamespace(), namespace, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkedByPolicy reports if an AlertPolicy resource for the New Relic policy lists the channel
+func (s *AlertChannel) linkedByPolicy(ctx context.Context, policyID int) (bool, error) {
+	kube := getKubeClient(ctx)
+	if kube == nil {
+		return false, nil
+	}
+
+	policies := &AlertPolicyList{}
+	err := kube.List(ctx, policies)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		id := policy.Status.GetID()
+		if id == nil || *id != policyID {
+			continue
+		}
+		if containsString(policy.Spec.Channels, s.newRelicName()) || policy.UsesAlertChannel(s.GetNamespace(), s.GetName()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,410 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAlertChannelLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "channel"},
+		Spec: AlertChannelSpec{
+			Type: string(alerts.ChannelTypes.Email),
+			Configuration: data{
+				"recipients": {Value: "test@example.com"},
+			},
+		},
+	}
+
+	if channel.Create(ctx) {
+		t.Fatalf("create failed: %s", channel.Status.Info)
+	}
+
+	result, err := account.GetChannel(*channel.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Configuration.Recipients != "test@example.com" {
+		t.Fatalf("unexpected configuration %+v", result.Configuration)
+	}
+
+	if channel.Delete(ctx) {
+		t.Fatalf("delete failed: %s", channel.Status.Info)
+	}
+	if _, err = account.GetChannel(result.ID); err == nil {
+		t.Fatal("expected channel to be deleted")
+	}
+}
+
+func TestAlertChannelSlackValidation(t *testing.T) {
+	ctx := WithClient(context.TODO(), fake.New())
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "channel"},
+		Spec: AlertChannelSpec{
+			Type: string(alerts.ChannelTypes.Slack),
+			Configuration: data{
+				"channel": {Value: "#alerts"},
+			},
+		},
+	}
+
+	if !channel.Create(ctx) {
+		t.Fatal("expected create to fail without a slack url")
+	}
+	if channel.IsCreated() {
+		t.Fatal("expected channel to not be created")
+	}
+}
+
+func TestAlertChannelPolicies(t *testing.T) {
+	account := fake.NewAccount()
+	ids := map[string]int{}
+	for _, name := range []string{"first", "second", "owned"} {
+		policy, err := account.CreatePolicy(alerts.Policy{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[name] = policy.ID
+	}
+
+	// the owned policy lists the channel itself, detaching it from the channel side would fight the policy
+	owner := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "owned", Namespace: "team"},
+		Spec:       AlertPolicySpec{Channels: []string{"oncall"}},
+	}
+	owner.Status.SetID(ids["owned"])
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), clientfake.NewFakeClientWithScheme(s, owner))
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "team"},
+		Spec: AlertChannelSpec{
+			Type:          "email",
+			Configuration: data{"recipients": {Value: "oncall@example.com"}},
+			Policies:      []string{"first", "second", "owned"},
+		},
+	}
+
+	if channel.Create(ctx) {
+		t.Fatalf("create failed: %s", channel.Status.Info)
+	}
+
+	linked := func() []int {
+		data, err := account.GetChannel(*channel.Status.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data.Links.PolicyIDs
+	}
+	if got := linked(); len(got) != 3 || len(channel.Status.Policies) != 3 {
+		t.Fatalf("expected channel to be attached to every policy, got %v", got)
+	}
+
+	channel.Spec.Policies = []string{"first"}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if got := linked(); len(got) != 2 || !containsInt(got, ids["first"]) || !containsInt(got, ids["owned"]) {
+		t.Fatalf("expected channel to be detached from the second policy only, got %v", got)
+	}
+
+	if _, err := account.DeletePolicyChannel(ids["first"], *channel.Status.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if got := linked(); !containsInt(got, ids["first"]) || !channel.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatalf("expected channel to be attached again, got %v", got)
+	}
+}
+
+func TestAlertChannelPolicyRefs(t *testing.T) {
+	account := fake.NewAccount()
+
+	// policies sharing a name can only be told apart by reference
+	if _, err := account.CreatePolicy(alerts.Policy{Name: "platform"}); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "platform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource := &AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "shared"}}
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s, resource)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "team"},
+		Spec: AlertChannelSpec{
+			Email:      &EmailChannel{Recipients: []string{"oncall@example.com"}},
+			PolicyRefs: []ObjectReference{{Name: "platform", Namespace: "shared"}},
+		},
+	}
+	if !channel.UsesAlertPolicy("shared", "platform") || channel.UsesAlertPolicy("team", "platform") {
+		t.Fatal("expected the channel to use the referenced policy only")
+	}
+
+	if !channel.Create(ctx) {
+		t.Fatal("expected create to wait for the policy")
+	}
+	if c := channel.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotReady {
+		t.Fatalf("expected to wait for the policy, got %+v", channel.Status.Conditions)
+	}
+
+	resource.Status.SetID(policy.ID)
+	if err := kube.Update(context.TODO(), resource); err != nil {
+		t.Fatal(err)
+	}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+
+	linked := func() []int {
+		data, err := account.GetChannel(*channel.Status.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data.Links.PolicyIDs
+	}
+	if got := linked(); len(got) != 1 || got[0] != policy.ID {
+		t.Fatalf("expected channel to be attached to the referenced policy, got %v", got)
+	}
+
+	channel.Spec.PolicyRefs = nil
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if got := linked(); len(got) != 0 {
+		t.Fatalf("expected channel to be detached, got %v", got)
+	}
+
+	channel.Spec.PolicyRefs = []ObjectReference{{}}
+	if err := channel.validate(); !IsInvalidSpec(err) {
+		t.Fatalf("expected a reference without a name to be rejected, got %v", err)
+	}
+}
+
+func TestAlertChannelSecretConfiguration(t *testing.T) {
+	account := fake.NewAccount()
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team"},
+		Data:       map[string][]byte{"url": []byte("https://hooks.slack.com/first")},
+	}
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "team"},
+		Spec: AlertChannelSpec{
+			Type: string(alerts.ChannelTypes.Slack),
+			Configuration: data{
+				"channel": {Value: "#alerts"},
+				"url": {ValueFrom: &ConfigurationValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "url"},
+				}},
+			},
+		},
+	}
+	if !channel.UsesSecret("slack") || channel.UsesSecret("other") {
+		t.Fatal("expected the channel to use the slack Secret only")
+	}
+
+	if !channel.Create(ctx) {
+		t.Fatal("expected create to wait for the Secret")
+	}
+	if c := channel.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotFound {
+		t.Fatalf("expected missing Secret, got %+v", channel.Status.Conditions)
+	}
+
+	if err := kube.Create(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if channel.Create(ctx) {
+		t.Fatalf("create failed: %s", channel.Status.Info)
+	}
+
+	first, err := account.GetChannel(*channel.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Configuration.URL != "https://hooks.slack.com/first" {
+		t.Fatalf("expected url to be read from the Secret, got %+v", first.Configuration)
+	}
+	if _, err := account.UpdatePolicyChannels(policy.ID, []int{first.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// an unchanged Secret leaves the channel alone
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if *channel.Status.GetID() != first.ID {
+		t.Fatal("expected channel to be kept")
+	}
+
+	secret.Data["url"] = []byte("https://hooks.slack.com/second")
+	if err := kube.Update(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+
+	second, err := account.GetChannel(*channel.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID == first.ID || second.Configuration.URL != "https://hooks.slack.com/second" {
+		t.Fatalf("expected channel to be recreated with the rotated url, got %+v", second)
+	}
+	if !containsInt(second.Links.PolicyIDs, policy.ID) {
+		t.Fatalf("expected recreated channel to keep its policies, got %v", second.Links.PolicyIDs)
+	}
+	if _, err := account.GetChannel(first.ID); err == nil {
+		t.Fatal("expected the old channel to be deleted")
+	}
+}
+
+func TestAlertChannelReplace(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall"},
+		Spec:       AlertChannelSpec{Email: &EmailChannel{Recipients: []string{"first@example.com"}}},
+	}
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, channel)
+
+	if channel.Create(ctx) {
+		t.Fatalf("create failed: %s", channel.Status.Info)
+	}
+	first := *channel.Status.GetID()
+
+	// attached from the policy side, the replacement has to keep it
+	if _, err := account.UpdatePolicyChannels(policy.ID, []int{first}); err != nil {
+		t.Fatal(err)
+	}
+
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if *channel.Status.GetID() != first || len(recorder.Events) != 0 {
+		t.Fatal("expected an unchanged channel to be kept")
+	}
+
+	channel.Spec.Email.Recipients = []string{"second@example.com"}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+
+	data, err := account.GetChannel(*channel.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.ID == first || data.Configuration.Recipients != "second@example.com" {
+		t.Fatalf("expected channel to be replaced, got %+v", data)
+	}
+	if !containsInt(data.Links.PolicyIDs, policy.ID) {
+		t.Fatalf("expected replacement to be attached to the policy, got %v", data.Links.PolicyIDs)
+	}
+	if _, err := account.GetChannel(first); err == nil {
+		t.Fatal("expected the old channel to be deleted")
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected the replacement to be recorded, got %d events", len(recorder.Events))
+	}
+}
+
+func TestAlertChannelAdoptedWithoutHash(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	existing, err := account.CreateChannel(alerts.Channel{Name: "oncall", Type: alerts.ChannelTypes.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "oncall"},
+		Spec:       AlertChannelSpec{Email: &EmailChannel{Recipients: []string{"oncall@example.com"}}},
+	}
+	channel.Status.SetID(existing.ID)
+
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if *channel.Status.GetID() != existing.ID || channel.Status.ConfigurationHash == nil {
+		t.Fatal("expected a channel with a matching name and type to be kept")
+	}
+
+	channel.Spec.Email = nil
+	channel.Spec.User = &UserChannel{UserID: "1"}
+	if channel.Update(ctx) {
+		t.Fatalf("update failed: %s", channel.Status.Info)
+	}
+	if *channel.Status.GetID() == existing.ID {
+		t.Fatal("expected a change of type to replace the channel")
+	}
+}
+
+func TestAlertChannelName(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "on-call"},
+		Spec: AlertChannelSpec{
+			Name:  "On Call",
+			Email: &EmailChannel{Recipients: []string{"oncall@example.com"}},
+		},
+	}
+
+	if channel.Create(ctx) {
+		t.Fatalf("create failed: %s", channel.Status.Info)
+	}
+
+	result, err := account.GetChannel(*channel.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "On Call" {
+		t.Fatalf("expected the channel to be named by spec.name, got %s", result.Name)
+	}
+}
@@ -0,0 +1,335 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertConditionSpec defines the desired state of AlertCondition, a metric condition on APM applications
+type AlertConditionSpec struct {
+	// PolicyName is the name of the New Relic policy the condition belongs to
+	PolicyName string `json:"policyName,omitempty"`
+	// PolicyRef is the AlertPolicy resource the condition belongs to, it takes precedence over policyName
+	PolicyRef *ObjectReference `json:"policyRef,omitempty"`
+	// Applications are the names of the APM applications the condition applies to
+	// +kubebuilder:validation:MinItems=1
+	Applications []string `json:"applications"`
+	// Metric is evaluated for each application
+	// +kubebuilder:validation:Enum=apdex;error_percentage;response_time_web;response_time_background;throughput_web;throughput_background;user_defined
+	Metric string `json:"metric"`
+	// UserDefined is the custom metric evaluated when metric is user_defined
+	UserDefined *AlertConditionUserDefined `json:"userDefined,omitempty"`
+	// ConditionScope evaluates the metric for the whole application or for each instance, defaults to application
+	// +kubebuilder:validation:Enum=application;instance
+	ConditionScope string `json:"conditionScope,omitempty"`
+	// Critical opens a critical violation
+	Critical AlertThreshold `json:"critical"`
+	// Warning opens a warning violation
+	Warning *AlertThreshold `json:"warning,omitempty"`
+	// ViolationCloseTimerHours closes violations that stay open longer
+	// +kubebuilder:validation:Enum=1;2;4;8;12;24
+	ViolationCloseTimerHours int    `json:"violationCloseTimerHours,omitempty"`
+	RunbookURL               string `json:"runbookURL,omitempty"`
+	// Enabled defaults to true
+	Enabled    *bool                        `json:"enabled,omitempty"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// AlertConditionUserDefined is a custom metric reported by the applications
+type AlertConditionUserDefined struct {
+	Metric string `json:"metric"`
+	// +kubebuilder:validation:Enum=average;min;max;total;sample_size
+	ValueFunction string `json:"valueFunction"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AlertCondition is the Schema for the alertconditions API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=alertconditions,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type AlertCondition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              AlertConditionSpec `json:"spec"`
+	Status            Status             `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AlertConditionList contains a list of AlertCondition
+type AlertConditionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []AlertCondition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AlertCondition{}, &AlertConditionList{})
+}
+
+// Additional Code
+
+var _ CRD = &AlertCondition{}
+
+// IsCreated specifies if the object has been created in new relic yet
+func (s *AlertCondition) IsCreated() bool {
+	return s.Status.IsCreated()
+}
+
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *AlertCondition) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *AlertCondition) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *AlertCondition) GetStatus() *Status {
+	return &s.Status
+}
+
+func (s *AlertCondition) policyID(ctx context.Context) (int, error) {
+	return resolvePolicyID(ctx, s, s.Spec.PolicyName, s.Spec.PolicyRef)
+}
+
+// UsesAlertPolicy reports if the condition refers to the AlertPolicy resource namespace/name
+func (s *AlertCondition) UsesAlertPolicy(namespace string, name string) bool {
+	return s.Spec.PolicyRef != nil && s.Spec.PolicyRef.refersTo(s.GetNamespace(), namespace, name)
+}
+
+func (s *AlertCondition) toNewRelic(entities []string) (*alerts.Condition, error) {
+	if len(s.Spec.Applications) == 0 {
+		return nil, errors.New("at least one application is required")
+	}
+
+	metric := alerts.MetricType(s.Spec.Metric)
+	if metric == alerts.MetricTypes.UserDefined && s.Spec.UserDefined == nil {
+		return nil, errors.New("userDefined is required for the user_defined metric")
+	}
+	if metric != alerts.MetricTypes.UserDefined && s.Spec.UserDefined != nil {
+		return nil, errors.New("userDefined can only be set for the user_defined metric")
+	}
+
+	scope := s.Spec.ConditionScope
+	if scope == "" {
+		scope = "application"
+	}
+
+	data := alerts.Condition{
+		Name:                s.GetName(),
+		Type:                alerts.ConditionTypes.APMApplicationMetric,
+		Entities:            entities,
+		Metric:              metric,
+		Scope:               scope,
+		RunbookURL:          s.Spec.RunbookURL,
+		ViolationCloseTimer: s.Spec.ViolationCloseTimerHours,
+		Enabled:             s.Spec.Enabled == nil || *s.Spec.Enabled,
+		Terms:               []alerts.ConditionTerm{s.Spec.Critical.toNewRelic(alerts.PriorityTypes.Critical)},
+	}
+
+	if s.Spec.UserDefined != nil {
+		data.UserDefined = alerts.ConditionUserDefined{
+			Metric:        s.Spec.UserDefined.Metric,
+			ValueFunction: alerts.ValueFunctionType(s.Spec.UserDefined.ValueFunction),
+		}
+	}
+
+	if s.Spec.Warning != nil {
+		data.Terms = append(data.Terms, s.Spec.Warning.toNewRelic(alerts.PriorityTypes.Warning))
+	}
+
+	if s.Status.ID != nil {
+		data.ID = int(*s.Status.GetID())
+	}
+	return &data, nil
+}
+
+// Adopt takes over an existing condition of the policy matching importID, or matching the name when adoptExisting is set
+func (s *AlertCondition) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID == nil && !s.Spec.AdoptExisting {
+		return false
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Alerts.GetCondition(policyID, id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
+	}
+
+	items, err := GetClient(ctx).Alerts.ListConditions(policyID)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	ids := []int{}
+	for _, item := range items {
+		if item.Name == s.GetName() {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.GetName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
+}
+
+// Create in newrelic
+func (s *AlertCondition) Create(ctx context.Context) bool {
+	entities, err := findApplicationIDs(ctx, s.Spec.Applications)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	input, err := s.toNewRelic(entities)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	data, err := GetClient(ctx).Alerts.CreateCondition(policyID, *input)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	s.Status.SetID(data.ID)
+	s.Status.Hash = hash
+	return false
+}
+
+// Delete in newrelic
+func (s *AlertCondition) Delete(ctx context.Context) bool {
+	logger := GetLogger(ctx)
+
+	id := s.Status.GetID()
+	if id == nil {
+		logger.Info("object does not exist")
+		return false
+	}
+
+	// conditions are removed along with their policy
+	_, err := GetClient(ctx).Alerts.DeleteCondition(*id)
+	if isNotFound(err) {
+		return false
+	}
+	return s.Status.HandleOnError(ctx, err)
+}
+
+// Update object in newrelic
+func (s *AlertCondition) Update(ctx context.Context) bool {
+	entities, err := findApplicationIDs(ctx, s.Spec.Applications)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	input, err := s.toNewRelic(entities)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	current, err := GetClient(ctx).Alerts.GetCondition(policyID, input.ID)
+	if isNotFound(err) {
+		// conditions can not be moved, one that still exists belongs to the previous policy
+		_, err = GetClient(ctx).Alerts.DeleteCondition(input.ID)
+		if isNotFound(err) {
+			s.Status.markDeletedRemote(ctx)
+		} else if s.Status.HandleOnError(ctx, err) {
+			return true
+		} else {
+			GetLogger(ctx).Info("moving condition to another policy", "policy", policyID)
+			s.Status.ID = nil
+		}
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	fields := drift{}
+	fields.check("name", input.Name, current.Name)
+	fields.check("applications", sortedStrings(input.Entities), sortedStrings(current.Entities))
+	fields.check("metric", input.Metric, current.Metric)
+	fields.check("userDefined", input.UserDefined, current.UserDefined)
+	fields.check("conditionScope", input.Scope, current.Scope)
+	fields.check("thresholds", sortedTerms(input.Terms), sortedTerms(current.Terms))
+	if input.ViolationCloseTimer != 0 {
+		fields.check("violationCloseTimerHours", input.ViolationCloseTimer, current.ViolationCloseTimer)
+	}
+	fields.check("runbookURL", input.RunbookURL, current.RunbookURL)
+	fields.check("enabled", input.Enabled, current.Enabled)
+
+	if len(fields) > 0 {
+		_, err = GetClient(ctx).Alerts.UpdateCondition(*input)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		if !s.Status.specChanged(hash) {
+			s.Status.markDrift(ctx, fields)
+		} else {
+			s.Status.markInSync()
+		}
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
+	return false
+}
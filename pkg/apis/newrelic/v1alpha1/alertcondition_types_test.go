@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAlertConditionLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := account.AddApplication("web")
+	account.AddApplication("web-staging")
+
+	condition := &AlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "apdex"},
+		Spec: AlertConditionSpec{
+			PolicyName:   "policy",
+			Applications: []string{"web"},
+			Metric:       "apdex",
+			Critical:     AlertThreshold{Operator: "below", Threshold: 0.7, DurationMinutes: 5},
+		},
+	}
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+
+	data, err := account.GetCondition(policy.ID, *condition.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Type != alerts.ConditionTypes.APMApplicationMetric || data.Scope != "application" {
+		t.Fatalf("unexpected defaults %+v", data)
+	}
+	if len(data.Entities) != 1 || data.Entities[0] != strconv.Itoa(app.ID) {
+		t.Fatalf("expected only the web application, got %v", data.Entities)
+	}
+
+	condition.Spec.Metric = "error_percentage"
+	condition.Spec.Critical = AlertThreshold{Threshold: 5, DurationMinutes: 5}
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	data, err = account.GetCondition(policy.ID, data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Metric != alerts.MetricTypes.ErrorPercentage || data.Terms[0].Operator != alerts.OperatorTypes.Above {
+		t.Fatalf("expected condition to be updated, got %+v", data)
+	}
+
+	if condition.Delete(ctx) {
+		t.Fatalf("delete failed: %s", condition.Status.Info)
+	}
+	if _, err = account.GetCondition(policy.ID, data.ID); err == nil {
+		t.Fatal("expected condition to be deleted")
+	}
+}
+
+func TestAlertConditionMissingApplication(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	if _, err := account.CreatePolicy(alerts.Policy{Name: "policy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := &AlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "apdex"},
+		Spec: AlertConditionSpec{
+			PolicyName:   "policy",
+			Applications: []string{"web"},
+			Metric:       "apdex",
+			Critical:     AlertThreshold{Threshold: 0.7, DurationMinutes: 5},
+		},
+	}
+
+	if !condition.Create(ctx) {
+		t.Fatal("expected create to wait for the application")
+	}
+	if c := condition.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotFound {
+		t.Fatalf("expected missing dependency, got %+v", condition.Status.Conditions)
+	}
+}
@@ -3,15 +3,30 @@ package v1alpha1
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // AlertPolicySpec defines the desired state of AlertPolicy
 type AlertPolicySpec struct {
-	IncidentPreference string   `json:"incident_preference,omitempty"`
-	Channels           []string `json:"channels,omitempty"`
+	// Name of the New Relic policy, defaults to the name of the resource
+	Name               string `json:"name,omitempty"`
+	IncidentPreference string `json:"incident_preference,omitempty"`
+	// Channels are the names of New Relic channels to notify
+	Channels []string `json:"channels,omitempty"`
+	// ChannelRefs are AlertChannel resources to notify
+	ChannelRefs []ObjectReference            `json:"channelRefs,omitempty"`
+	AccountRef  *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -19,6 +34,10 @@ type AlertPolicySpec struct {
 // AlertPolicy is the Schema for the alertpolicies API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=alertpolicies,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type AlertPolicy struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
@@ -48,9 +67,43 @@ func (s *AlertPolicy) IsCreated() bool {
 	return s.Status.IsCreated()
 }
 
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *AlertPolicy) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// newRelicName returns the name of the New Relic policy
+func (s *AlertPolicy) newRelicName() string {
+	if s.Spec.Name != "" {
+		return s.Spec.Name
+	}
+	return s.GetName()
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *AlertPolicy) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *AlertPolicy) GetStatus() *Status {
+	return &s.Status
+}
+
 func (s *AlertPolicy) toNewRelic() (*alerts.Policy, error) {
+	switch alerts.IncidentPreferenceType(s.Spec.IncidentPreference) {
+	case "", alerts.IncidentPreferenceTypes.PerPolicy, alerts.IncidentPreferenceTypes.PerCondition, alerts.IncidentPreferenceTypes.PerConditionAndTarget:
+	default:
+		return nil, fmt.Errorf("unknown incident_preference %s, expected one of PER_POLICY, PER_CONDITION or PER_CONDITION_AND_TARGET", s.Spec.IncidentPreference)
+	}
+	for _, ref := range s.Spec.ChannelRefs {
+		if ref.Name == "" {
+			return nil, errors.New("references require a name")
+		}
+	}
+
 	data := alerts.Policy{
-		Name:               s.GetObjectMeta().GetName(),
+		Name:               s.newRelicName(),
 		IncidentPreference: alerts.IncidentPreferenceType(s.Spec.IncidentPreference),
 	}
 
@@ -60,25 +113,76 @@ func (s *AlertPolicy) toNewRelic() (*alerts.Policy, error) {
 	return &data, nil
 }
 
+// Adopt takes over an existing policy matching importID, or matching the name when adoptExisting is set
+func (s *AlertPolicy) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Alerts.GetPolicy(id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
+	}
+
+	if !s.Spec.AdoptExisting {
+		return false
+	}
+
+	items, err := GetClient(ctx).Alerts.ListPolicies(&alerts.ListPoliciesParams{Name: s.newRelicName()})
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	ids := []int{}
+	for _, item := range items {
+		if item.Name == s.newRelicName() {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.newRelicName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
+}
+
 // Create in newrelic
 func (s *AlertPolicy) Create(ctx context.Context) bool {
 	input, err := s.toNewRelic()
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	data, err := client.Alerts.CreatePolicy(*input)
+	data, err := GetClient(ctx).Alerts.CreatePolicy(*input)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
 	s.Status.SetID(data.ID)
-	s.SetFinalizers([]string{finalizer})
 
-	err = s.addChannels(ctx)
+	_, err = s.addChannels(ctx)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
+
+	s.Status.Hash = hash
 	return false
 }
 
@@ -92,7 +196,7 @@ func (s *AlertPolicy) Delete(ctx context.Context) bool {
 		return false
 	}
 
-	_, err := client.Alerts.DeletePolicy(int(*id))
+	_, err := GetClient(ctx).Alerts.DeletePolicy(int(*id))
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
@@ -103,59 +207,133 @@ func (s *AlertPolicy) Delete(ctx context.Context) bool {
 // Update object in newrelic
 func (s *AlertPolicy) Update(ctx context.Context) bool {
 	input, err := s.toNewRelic()
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	_, err = client.Alerts.UpdatePolicy(*input)
+	current, err := GetClient(ctx).Alerts.GetPolicy(input.ID)
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
 	if s.Status.HandleOnError(ctx, err) {
-		if err.Error() == "resource not found" {
-			s.Status.ID = nil
-		}
 		return true
 	}
 
-	err = s.addChannels(ctx)
+	fields := drift{}
+	fields.check("name", input.Name, current.Name)
+	if input.IncidentPreference != "" {
+		fields.check("incident_preference", input.IncidentPreference, current.IncidentPreference)
+	}
+
+	if len(fields) > 0 {
+		_, err = GetClient(ctx).Alerts.UpdatePolicy(*input)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+	}
+
+	linked, err := s.addChannels(ctx)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
+	if len(linked) > 0 {
+		fields = append(fields, "channels")
+	}
+
+	if len(fields) > 0 && !s.Status.specChanged(hash) {
+		s.Status.markDrift(ctx, fields)
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
+	return false
+}
 
+// UsesAlertChannel reports if the policy refers to the AlertChannel resource namespace/name
+func (s *AlertPolicy) UsesAlertChannel(namespace string, name string) bool {
+	for _, ref := range s.Spec.ChannelRefs {
+		if ref.refersTo(s.GetNamespace(), namespace, name) {
+			return true
+		}
+	}
 	return false
 }
 
-func (s *AlertPolicy) addChannels(ctx context.Context) error {
+// addChannels links the channels to the policy and returns the names of the channels that were not linked yet
+func (s *AlertPolicy) addChannels(ctx context.Context) ([]string, error) {
 	logger := GetLogger(ctx)
+	linked := []string{}
+
+	if s.Spec.Channels == nil && s.Spec.ChannelRefs == nil {
+		return linked, nil
+	}
 
-	if s.Spec.Channels != nil {
-		channels, err := client.Alerts.ListChannels()
+	channels, err := GetClient(ctx).Alerts.ListChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	id := s.Status.GetID()
+	if id == nil {
+		return nil, errors.New("id is nil")
+	}
+
+	wanted := []*alerts.Channel{}
+	for _, channel := range s.Spec.Channels {
+		found := false
+		for _, item := range channels {
+			if channel == item.Name {
+				wanted = append(wanted, item)
+				found = true
+				break
+			}
+		}
+		if !found {
+			logger.Info("unable to find channel", "channel", channel)
+		}
+	}
+
+	for _, ref := range s.Spec.ChannelRefs {
+		channelID, err := resolveID(ctx, s, "AlertChannel", ref, &AlertChannel{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		channelIds := []int{}
-		for _, channel := range s.Spec.Channels {
-			found := false
-			for _, item := range channels {
-				if channel == item.Name {
-					channelIds = append(channelIds, item.ID)
-					found = true
-					break
-				}
-			}
-			if !found {
-				logger.Info("unable to find channel", "channel", channel)
+		found := false
+		for _, item := range channels {
+			if item.ID == channelID {
+				wanted = append(wanted, item)
+				found = true
+				break
 			}
 		}
-
-		id := s.Status.GetID()
-		if id == nil {
-			return errors.New("id is nil")
+		if !found {
+			return nil, dependencyNotReady(fmt.Errorf("waiting for AlertChannel %s to be recreated in New Relic", ref.key(s.GetNamespace())))
 		}
+	}
 
-		_, err = client.Alerts.UpdatePolicyChannels(int(*id), channelIds)
-		if err != nil {
-			return err
+	channelIds := []int{}
+	for _, item := range wanted {
+		if !containsInt(item.Links.PolicyIDs, *id) && !containsInt(channelIds, item.ID) {
+			channelIds = append(channelIds, item.ID)
+			linked = append(linked, item.Name)
 		}
 	}
-	return nil
+
+	if len(channelIds) == 0 {
+		return linked, nil
+	}
+
+	_, err = GetClient(ctx).Alerts.UpdatePolicyChannels(int(*id), channelIds)
+	if err != nil {
+		return nil, err
+	}
+	return linked, nil
 }
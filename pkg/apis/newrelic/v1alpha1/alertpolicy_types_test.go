@@ -0,0 +1,209 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAlertPolicyLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	channel, err := account.CreateChannel(alerts.Channel{Name: "channel", Type: alerts.ChannelTypes.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: AlertPolicySpec{
+			IncidentPreference: string(alerts.IncidentPreferenceTypes.PerCondition),
+			Channels:           []string{"channel"},
+		},
+	}
+
+	if policy.Create(ctx) {
+		t.Fatalf("create failed: %s", policy.Status.Info)
+	}
+
+	data, err := account.GetPolicy(*policy.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.IncidentPreference != alerts.IncidentPreferenceTypes.PerCondition {
+		t.Fatalf("unexpected incident preference %s", data.IncidentPreference)
+	}
+
+	channel, err = account.GetChannel(channel.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channel.Links.PolicyIDs) != 1 || channel.Links.PolicyIDs[0] != data.ID {
+		t.Fatalf("expected channel to be linked to the policy, got %v", channel.Links.PolicyIDs)
+	}
+
+	policy.Spec.IncidentPreference = string(alerts.IncidentPreferenceTypes.PerPolicy)
+	if policy.Update(ctx) {
+		t.Fatalf("update failed: %s", policy.Status.Info)
+	}
+
+	data, err = account.GetPolicy(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.IncidentPreference != alerts.IncidentPreferenceTypes.PerPolicy {
+		t.Fatalf("expected incident preference to be updated, got %s", data.IncidentPreference)
+	}
+
+	if policy.Delete(ctx) {
+		t.Fatalf("delete failed: %s", policy.Status.Info)
+	}
+	if _, err = account.GetPolicy(data.ID); err == nil {
+		t.Fatal("expected policy to be deleted")
+	}
+}
+
+func TestAlertPolicyUpdateNotFound(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+	}
+	policy.Status.SetID(1234)
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, policy)
+
+	if policy.Update(ctx) {
+		t.Fatalf("update failed: %s", policy.Status.Info)
+	}
+	if !policy.IsCreated() || *policy.Status.GetID() == 1234 {
+		t.Fatalf("expected a missing policy to be recreated, got %v", policy.Status.ID)
+	}
+	if _, err := account.GetPolicy(*policy.Status.GetID()); err != nil {
+		t.Fatal(err)
+	}
+
+	condition := policy.Status.GetCondition(ConditionDrifted)
+	if condition == nil || condition.Reason != ReasonDeletedRemote {
+		t.Fatalf("expected drifted condition, got %+v", condition)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(recorder.Events))
+	}
+}
+
+func TestAlertPolicyDrift(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: AlertPolicySpec{
+			IncidentPreference: string(alerts.IncidentPreferenceTypes.PerPolicy),
+		},
+	}
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, policy)
+
+	if policy.Create(ctx) {
+		t.Fatalf("create failed: %s", policy.Status.Info)
+	}
+
+	if policy.Update(ctx) {
+		t.Fatalf("update failed: %s", policy.Status.Info)
+	}
+	if policy.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatal("expected an unchanged policy to be in sync")
+	}
+
+	data, err := account.GetPolicy(*policy.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.IncidentPreference = alerts.IncidentPreferenceTypes.PerConditionAndTarget
+	if _, err = account.UpdatePolicy(*data); err != nil {
+		t.Fatal(err)
+	}
+
+	if policy.Update(ctx) {
+		t.Fatalf("update failed: %s", policy.Status.Info)
+	}
+
+	data, err = account.GetPolicy(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.IncidentPreference != alerts.IncidentPreferenceTypes.PerPolicy {
+		t.Fatalf("expected drift to be repaired, got %s", data.IncidentPreference)
+	}
+
+	condition := policy.Status.GetCondition(ConditionDrifted)
+	if condition == nil || condition.Reason != ReasonDriftRepaired {
+		t.Fatalf("expected drifted condition, got %+v", condition)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(recorder.Events))
+	}
+}
+
+func TestAlertPolicyChannelRefs(t *testing.T) {
+	account := fake.NewAccount()
+
+	// channels sharing a name can only be told apart by reference
+	if _, err := account.CreateChannel(alerts.Channel{Name: "oncall", Type: alerts.ChannelTypes.Email}); err != nil {
+		t.Fatal(err)
+	}
+	channel, err := account.CreateChannel(alerts.Channel{Name: "oncall", Type: alerts.ChannelTypes.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource := &AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "shared"}}
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s, resource)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "team"},
+		Spec: AlertPolicySpec{
+			ChannelRefs: []ObjectReference{{Name: "oncall", Namespace: "shared"}},
+		},
+	}
+
+	if !policy.Create(ctx) {
+		t.Fatal("expected create to wait for the channel")
+	}
+	if c := policy.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotReady || policy.Status.Phase != PhasePending {
+		t.Fatalf("expected to wait for the channel, got %+v", policy.Status.Conditions)
+	}
+	if !policy.UsesAlertChannel("shared", "oncall") || policy.UsesAlertChannel("team", "oncall") {
+		t.Fatal("expected the policy to use the referenced channel only")
+	}
+
+	resource.Status.SetID(channel.ID)
+	if err := kube.Update(context.TODO(), resource); err != nil {
+		t.Fatal(err)
+	}
+
+	if policy.Update(ctx) {
+		t.Fatalf("update failed: %s", policy.Status.Info)
+	}
+
+	channels, err := account.ListChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range channels {
+		linked := containsInt(item.Links.PolicyIDs, *policy.Status.GetID())
+		if linked != (item.ID == channel.ID) {
+			t.Fatalf("expected only the referenced channel to be linked, got %+v", channels)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+)
+
+// findApplicationIDs returns the IDs of the APM applications with exactly these names
+func findApplicationIDs(ctx context.Context, names []string) ([]string, error) {
+	ids := []string{}
+	for _, name := range names {
+		items, err := GetClient(ctx).APM.ListApplications(&apm.ListApplicationsParams{Name: name})
+		if err != nil {
+			return nil, err
+		}
+
+		found := []int{}
+		for _, item := range items {
+			if item.Name == name {
+				found = append(found, item.ID)
+			}
+		}
+
+		if len(found) == 0 {
+			return nil, missingDependency(fmt.Errorf("unable to find application %s", name))
+		}
+		if len(found) > 1 {
+			return nil, invalidSpec(fmt.Errorf("found %d applications named %s", len(found), name))
+		}
+		ids = append(ids, strconv.Itoa(found[0]))
+	}
+	return ids, nil
+}
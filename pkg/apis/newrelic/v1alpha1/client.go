@@ -1,19 +1,75 @@
 package v1alpha1
 
 import (
-	"os"
+	"context"
 
-	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var client *nr.NewRelic
+type (
+	clientKey     struct{}
+	recorderKey   struct{}
+	kubeClientKey struct{}
+	accountsKey   struct{}
+)
+
+// Accounts finds the credentials of the New Relic account used by resources in namespace with the accountRef ref
+type Accounts interface {
+	CredentialsFor(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (newrelic.Credentials, error)
+}
+
+type eventTarget struct {
+	recorder record.EventRecorder
+	object   runtime.Object
+}
+
+// WithClient returns a new context with the provided New Relic client.
+func WithClient(ctx context.Context, client *newrelic.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// GetClient returns the New Relic client from the context
+func GetClient(ctx context.Context) *newrelic.Client {
+	client, _ := ctx.Value(clientKey{}).(*newrelic.Client)
+	return client
+}
+
+// WithKubeClient returns a new context with the client used to read ConfigMaps and Secrets referenced by the spec.
+func WithKubeClient(ctx context.Context, kube client.Reader) context.Context {
+	return context.WithValue(ctx, kubeClientKey{}, kube)
+}
+
+// getKubeClient returns the Kubernetes client from the context
+func getKubeClient(ctx context.Context) client.Reader {
+	kube, _ := ctx.Value(kubeClientKey{}).(client.Reader)
+	return kube
+}
+
+// WithAccounts returns a new context with the accounts used to check that references stay within one New Relic account.
+func WithAccounts(ctx context.Context, accounts Accounts) context.Context {
+	return context.WithValue(ctx, accountsKey{}, accounts)
+}
+
+// getAccounts returns the accounts from the context
+func getAccounts(ctx context.Context) Accounts {
+	accounts, _ := ctx.Value(accountsKey{}).(Accounts)
+	return accounts
+}
+
+// WithRecorder returns a new context recording events against object.
+func WithRecorder(ctx context.Context, recorder record.EventRecorder, object runtime.Object) context.Context {
+	return context.WithValue(ctx, recorderKey{}, eventTarget{recorder: recorder, object: object})
+}
 
-func init() {
-	var err error
-	// New Golang Client
-	apiKey := os.Getenv("NEW_RELIC_APIKEY")
-	client, err = nr.New(nr.ConfigAdminAPIKey(apiKey))
-	if err != nil {
-		panic(err)
+// recordEvent records an event when the context has a recorder
+func recordEvent(ctx context.Context, eventtype string, reason string, message string) {
+	target, ok := ctx.Value(recorderKey{}).(eventTarget)
+	if !ok {
+		return
 	}
+	target.recorder.Event(target.object, eventtype, reason, message)
 }
@@ -0,0 +1,202 @@
+package v1alpha1
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of a status condition
+type ConditionType string
+
+const (
+	// ConditionReady is true when the object exists in New Relic and matches the spec
+	ConditionReady ConditionType = "Ready"
+	// ConditionSynced is true when the last attempt to sync with New Relic succeeded
+	ConditionSynced ConditionType = "Synced"
+	// ConditionDependenciesResolved is true when every object the spec refers to was found
+	ConditionDependenciesResolved ConditionType = "DependenciesResolved"
+)
+
+// Reasons set on conditions
+const (
+	ReasonSynced             = "Synced"
+	ReasonPending            = "Pending"
+	ReasonInvalidSpec        = "InvalidSpec"
+	ReasonDependencyNotFound = "DependencyNotFound"
+	ReasonDependencyNotReady = "DependencyNotReady"
+	ReasonAPIError           = "APIError"
+	ReasonDeleting           = "Deleting"
+)
+
+// Phase is a summary of the conditions of an object
+type Phase string
+
+const (
+	PhasePending  Phase = "Pending"
+	PhaseReady    Phase = "Ready"
+	PhaseFailed   Phase = "Failed"
+	PhaseDeleting Phase = "Deleting"
+)
+
+// Condition follows the shape of the upstream metav1.Condition
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// ValidationError is returned when the spec can not be turned into a New Relic object
+// +k8s:deepcopy-gen=false
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// DependencyError is returned when an object the spec refers to does not exist yet
+// +k8s:deepcopy-gen=false
+type DependencyError struct {
+	Err error
+	// NotReady is set when the object exists but has not been created in New Relic yet
+	NotReady bool
+}
+
+func (e *DependencyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DependencyError) Unwrap() error {
+	return e.Err
+}
+
+func invalidSpec(err error) error {
+	var dependency *DependencyError
+	if err == nil || errors.As(err, &dependency) {
+		return err
+	}
+	return &ValidationError{Err: err}
+}
+
+// IsInvalidSpec reports if err was caused by a spec that has to be fixed before it can be synced
+func IsInvalidSpec(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+func missingDependency(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DependencyError{Err: err}
+}
+
+func dependencyNotReady(err error) error {
+	return &DependencyError{Err: err, NotReady: true}
+}
+
+// GetCondition returns the condition of the given type or nil
+func (s *Status) GetCondition(conditionType ConditionType) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue reports if the condition of the given type is true
+func (s *Status) IsConditionTrue(conditionType ConditionType) bool {
+	condition := s.GetCondition(conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// SetCondition adds or updates a condition, the transition time only changes along with the status
+func (s *Status) SetCondition(conditionType ConditionType, status corev1.ConditionStatus, reason string, message string) {
+	s.Conditions = setCondition(s.Conditions, s.ObservedGeneration, conditionType, status, reason, message)
+}
+
+func setCondition(conditions []Condition, generation int64, conditionType ConditionType, status corev1.ConditionStatus, reason string, message string) []Condition {
+	var condition *Condition
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			condition = &conditions[i]
+		}
+	}
+	if condition == nil {
+		conditions = append(conditions, Condition{Type: conditionType})
+		condition = &conditions[len(conditions)-1]
+	}
+
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Status = status
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = generation
+	return conditions
+}
+
+func (s *Status) markFailed(err error) {
+	reason := ReasonAPIError
+	var validationErr *ValidationError
+	var dependencyErr *DependencyError
+
+	switch {
+	case errors.As(err, &validationErr):
+		reason = ReasonInvalidSpec
+	case errors.As(err, &dependencyErr):
+		reason = ReasonDependencyNotFound
+		if dependencyErr.NotReady {
+			reason = ReasonDependencyNotReady
+		}
+		s.SetCondition(ConditionDependenciesResolved, corev1.ConditionFalse, reason, err.Error())
+	}
+
+	s.SetCondition(ConditionSynced, corev1.ConditionFalse, reason, err.Error())
+	s.SetCondition(ConditionReady, corev1.ConditionFalse, reason, err.Error())
+	s.Phase = PhaseFailed
+	if reason == ReasonDependencyNotReady {
+		// waiting on another resource is expected and resolves without intervention
+		s.Phase = PhasePending
+	}
+}
+
+func (s *Status) markSynced() {
+	now := metav1.Now()
+	s.LastSyncTime = &now
+
+	s.SetCondition(ConditionDependenciesResolved, corev1.ConditionTrue, ReasonSynced, "")
+	s.SetCondition(ConditionSynced, corev1.ConditionTrue, ReasonSynced, "")
+	if s.IsCreated() {
+		s.SetCondition(ConditionReady, corev1.ConditionTrue, ReasonSynced, "")
+		s.Phase = PhaseReady
+	} else {
+		s.SetCondition(ConditionReady, corev1.ConditionFalse, ReasonPending, "object has not been created in New Relic")
+		s.Phase = PhasePending
+	}
+}
+
+func (s *Status) markDeleting() {
+	s.SetCondition(ConditionReady, corev1.ConditionFalse, ReasonDeleting, "")
+	s.Phase = PhaseDeleting
+}
+
+// observe records the generation that was processed
+func (s *Status) observe(generation int64) {
+	s.ObservedGeneration = generation
+	for i := range s.Conditions {
+		s.Conditions[i].ObservedGeneration = generation
+	}
+}
@@ -2,8 +2,14 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,6 +18,10 @@ import (
 // Dashboard is the Schema for the dashboards API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=dashboards,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Dashboard struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
@@ -36,12 +46,74 @@ func init() {
 
 // DashboardSpec defines the structure of the dashboard for new relic
 type DashboardSpec struct {
-	Icon string `json:"icon,omitempty"`
-	// TODO
-	// Widgets    []dashboards.DashboardWidget `json:"widgets,omitempty"`
+	// Title of the New Relic dashboard, defaults to the name of the resource
+	Title      string `json:"title,omitempty"`
+	Icon       string `json:"icon,omitempty"`
 	Visibility string `json:"visibility,omitempty"`
 	Editable   string `json:"editable,omitempty"`
-	// Filter      `json:"filter,omitempty"`
+	// GridColumnCount is 3 for Insights dashboards and 12 for New Relic One dashboards
+	// +kubebuilder:validation:Enum=3;12
+	GridColumnCount int               `json:"gridColumnCount,omitempty"`
+	Filter          *DashboardFilter  `json:"filter,omitempty"`
+	Widgets         []DashboardWidget `json:"widgets,omitempty"`
+	// JSON is a dashboard exported from New Relic, the title is always spec.title or the resource name
+	JSON string `json:"json,omitempty"`
+	// JSONFrom reads the dashboard JSON from a ConfigMap or Secret key
+	JSONFrom   *DashboardJSONSource         `json:"jsonFrom,omitempty"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// DashboardJSONSource selects the key holding the dashboard JSON, only one may be set
+type DashboardJSONSource struct {
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *corev1.SecretKeySelector    `json:"secretKeyRef,omitempty"`
+}
+
+// DashboardFilter lets viewers filter the dashboard by event type and attribute
+type DashboardFilter struct {
+	EventTypes []string `json:"eventTypes,omitempty"`
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+// DashboardWidget is a single chart on the dashboard
+type DashboardWidget struct {
+	Title string `json:"title,omitempty"`
+	// Visualization is the New Relic visualization such as line_chart, billboard or markdown
+	Visualization string `json:"visualization"`
+	// NRQL is the query, required for everything but markdown widgets
+	NRQL string `json:"nrql,omitempty"`
+	// Source is the text of markdown widgets
+	Source string `json:"source,omitempty"`
+	Facet  string `json:"facet,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+	// AccountID queries another account, it defaults to the account of the dashboard
+	AccountID            int                       `json:"accountID,omitempty"`
+	DrilldownDashboardID int                       `json:"drilldownDashboardID,omitempty"`
+	Threshold            *DashboardWidgetThreshold `json:"threshold,omitempty"`
+	Layout               DashboardWidgetLayout     `json:"layout,omitempty"`
+}
+
+// DashboardWidgetThreshold colors billboard widgets
+type DashboardWidgetThreshold struct {
+	// +kubebuilder:validation:Type=number
+	Red *float64 `json:"red,omitempty"`
+	// +kubebuilder:validation:Type=number
+	Yellow *float64 `json:"yellow,omitempty"`
+}
+
+// DashboardWidgetLayout places the widget on the grid, rows and columns start at 1,
+// widgets without a row and column fill the free space row by row
+type DashboardWidgetLayout struct {
+	Row    int `json:"row,omitempty"`
+	Column int `json:"column,omitempty"`
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
 }
 
 var _ CRD = &Dashboard{}
@@ -51,14 +123,108 @@ func (s *Dashboard) IsCreated() bool {
 	return s.Status.IsCreated()
 }
 
-func (s *Dashboard) toNewRelic() (*dashboards.Dashboard, error) {
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *Dashboard) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// newRelicName returns the name of the New Relic dashboard
+func (s *Dashboard) newRelicName() string {
+	if s.Spec.Title != "" {
+		return s.Spec.Title
+	}
+	return s.GetName()
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *Dashboard) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *Dashboard) GetStatus() *Status {
+	return &s.Status
+}
+
+// UsesConfigMap reports if the dashboard JSON is read from the ConfigMap
+func (s *Dashboard) UsesConfigMap(name string) bool {
+	return s.Spec.JSONFrom != nil && s.Spec.JSONFrom.ConfigMapKeyRef != nil && s.Spec.JSONFrom.ConfigMapKeyRef.Name == name
+}
+
+// UsesSecret reports if the dashboard JSON is read from the Secret
+func (s *Dashboard) UsesSecret(name string) bool {
+	return s.Spec.JSONFrom != nil && s.Spec.JSONFrom.SecretKeyRef != nil && s.Spec.JSONFrom.SecretKeyRef.Name == name
+}
+
+// rawJSON returns the dashboard JSON from the spec or the referenced ConfigMap or Secret,
+// a missing or empty source is a missing dependency so the dashboard is left alone instead of emptied
+func (s *Dashboard) rawJSON(ctx context.Context) (string, error) {
+	source := s.Spec.JSONFrom
+	if source == nil {
+		return s.Spec.JSON, nil
+	}
+	if err := s.validateJSONFrom(); err != nil {
+		return "", err
+	}
+
+	var raw string
+	var err error
+	if source.ConfigMapKeyRef != nil {
+		raw, err = readConfigMapKey(ctx, s.GetNamespace(), source.ConfigMapKeyRef)
+	} else {
+		raw, err = readSecretKey(ctx, s.GetNamespace(), source.SecretKeyRef)
+	}
+	if err == nil && strings.TrimSpace(raw) == "" {
+		err = missingDependency(errors.New("jsonFrom is missing or empty"))
+	}
+	return raw, err
+}
+
+// validateJSONFrom checks that jsonFrom names exactly one source and is not combined with json
+func (s *Dashboard) validateJSONFrom() error {
+	source := s.Spec.JSONFrom
+	switch {
+	case s.Spec.JSON != "":
+		return invalidSpec(errors.New("json and jsonFrom can not be used together"))
+	case (source.ConfigMapKeyRef != nil) == (source.SecretKeyRef != nil):
+		return invalidSpec(errors.New("jsonFrom requires either configMapKeyRef or secretKeyRef"))
+	}
+	return nil
+}
+
+// validate checks the spec without calling New Relic, JSON read from a ConfigMap or Secret is only checked while syncing
+func (s *Dashboard) validate() error {
+	if s.Spec.JSONFrom != nil {
+		return s.validateJSONFrom()
+	}
+	_, err := s.toNewRelic(s.Spec.JSON)
+	return err
+}
+
+// hash covers the JSON read from a ConfigMap or Secret so changes to it are applied instead of seen as drift
+func (s *Dashboard) hash(raw string) ([]byte, error) {
+	if s.Spec.JSONFrom == nil {
+		return hashSpec(s.Spec)
+	}
+	return hashSpec([]interface{}{s.Spec, raw})
+}
+
+func (s *Dashboard) toNewRelic(raw string) (*dashboards.Dashboard, error) {
 	data := &dashboards.Dashboard{
-		Title:      s.GetName(),
+		Title:      s.newRelicName(),
 		Icon:       dashboards.DashboardIconType(s.Spec.Icon),
 		Visibility: dashboards.VisibilityType(s.Spec.Visibility),
 		Editable:   dashboards.EditableType(s.Spec.Editable),
 	}
 
+	if raw != "" {
+		if err := s.mergeJSON(data, raw); err != nil {
+			return nil, err
+		}
+	} else if err := s.mergeSpec(data); err != nil {
+		return nil, err
+	}
+
 	if s.Status.ID != nil {
 		data.ID = int(*s.Status.GetID())
 	}
@@ -75,21 +241,295 @@ func (s *Dashboard) toNewRelic() (*dashboards.Dashboard, error) {
 	return data, nil
 }
 
+// mergeSpec sets the filter and widgets defined in the spec
+func (s *Dashboard) mergeSpec(data *dashboards.Dashboard) error {
+	if s.Spec.GridColumnCount != 0 {
+		data.GridColumnCount = dashboards.GridColumnCountType(s.Spec.GridColumnCount)
+	}
+
+	if s.Spec.Filter != nil {
+		if len(s.Spec.Filter.Attributes) > 0 && len(s.Spec.Filter.EventTypes) == 0 {
+			return errors.New("filter attributes require at least one event type")
+		}
+		data.Filter = dashboards.DashboardFilter{
+			EventTypes: s.Spec.Filter.EventTypes,
+			Attributes: s.Spec.Filter.Attributes,
+		}
+	}
+
+	widgets, err := s.widgetsToNewRelic()
+	if err != nil {
+		return err
+	}
+	data.Widgets = widgets
+	return nil
+}
+
+// mergeJSON sets the layout from a dashboard exported from New Relic, settings in the spec take precedence
+func (s *Dashboard) mergeJSON(data *dashboards.Dashboard, raw string) error {
+	if len(s.Spec.Widgets) > 0 || s.Spec.Filter != nil || s.Spec.GridColumnCount != 0 {
+		return errors.New("json can not be combined with widgets, filter or gridColumnCount")
+	}
+
+	// the REST API wraps a single dashboard in a dashboard key, plain documents are accepted too
+	exported := struct {
+		Dashboard *dashboards.Dashboard `json:"dashboard"`
+	}{}
+	if err := json.Unmarshal([]byte(raw), &exported); err != nil {
+		return fmt.Errorf("invalid dashboard json %w", err)
+	}
+
+	dashboard := exported.Dashboard
+	if dashboard == nil {
+		dashboard = &dashboards.Dashboard{}
+		if err := json.Unmarshal([]byte(raw), dashboard); err != nil {
+			return fmt.Errorf("invalid dashboard json %w", err)
+		}
+	}
+
+	if data.Icon == "" {
+		data.Icon = dashboard.Icon
+	}
+	if data.Visibility == "" {
+		data.Visibility = dashboard.Visibility
+	}
+	if data.Editable == "" {
+		data.Editable = dashboard.Editable
+	}
+	data.GridColumnCount = dashboard.GridColumnCount
+	data.Filter = dashboard.Filter
+
+	// widget IDs belong to the dashboard the JSON was exported from
+	for _, widget := range dashboard.Widgets {
+		widget.ID = 0
+		data.Widgets = append(data.Widgets, widget)
+	}
+	return nil
+}
+
+// widgetsToNewRelic lays out the widgets on the grid of the dashboard
+func (s *Dashboard) widgetsToNewRelic() ([]dashboards.DashboardWidget, error) {
+	columns := s.Spec.GridColumnCount
+	if columns == 0 {
+		columns = int(dashboards.GridColumnCountTypes.Insights)
+	}
+	return layoutWidgets(s.Spec.Widgets, columns)
+}
+
+// layoutWidgets validates and converts the widgets,
+// widgets without a row and column are placed row by row in the first space left free by the other widgets
+func layoutWidgets(widgets []DashboardWidget, columns int) ([]dashboards.DashboardWidget, error) {
+	result := []dashboards.DashboardWidget{}
+	unplaced := []int{}
+	placed := []dashboards.DashboardWidgetLayout{}
+	for i, widget := range widgets {
+		data, err := widget.toNewRelic(columns)
+		if err != nil {
+			return nil, fmt.Errorf("widget %d %w", i+1, err)
+		}
+		result = append(result, *data)
+
+		if widget.Layout.Row == 0 && widget.Layout.Column == 0 {
+			unplaced = append(unplaced, i)
+		} else {
+			placed = append(placed, data.Layout)
+		}
+	}
+
+	for _, i := range unplaced {
+		layout := &result[i].Layout
+		layout.Row, layout.Column = 1, 1
+		for overlaps(*layout, placed) {
+			layout.Column++
+			if layout.Column+layout.Width-1 > columns {
+				layout.Row, layout.Column = layout.Row+1, 1
+			}
+		}
+		placed = append(placed, *layout)
+	}
+	return result, nil
+}
+
+// overlaps reports if layout shares a cell with any of the others
+func overlaps(layout dashboards.DashboardWidgetLayout, others []dashboards.DashboardWidgetLayout) bool {
+	for _, other := range others {
+		if layout.Row < other.Row+other.Height && other.Row < layout.Row+layout.Height &&
+			layout.Column < other.Column+other.Width && other.Column < layout.Column+layout.Width {
+			return true
+		}
+	}
+	return false
+}
+
+// metric based visualizations need entity and metric selection which is not supported
+var unsupportedVisualizations = []dashboards.VisualizationType{
+	dashboards.VisualizationTypes.ApplicationBreakdown,
+	dashboards.VisualizationTypes.MetricLineChart,
+}
+
+func (s *DashboardWidget) toNewRelic(columns int) (*dashboards.DashboardWidget, error) {
+	visualization := dashboards.VisualizationType(s.Visualization)
+	switch {
+	case visualization == "":
+		return nil, errors.New("requires a visualization")
+	case visualization == dashboards.VisualizationTypes.Markdown:
+		if s.Source == "" || s.NRQL != "" {
+			return nil, errors.New("markdown requires source and no nrql")
+		}
+	case s.NRQL == "":
+		return nil, fmt.Errorf("%s requires nrql", visualization)
+	}
+
+	for _, item := range unsupportedVisualizations {
+		if visualization == item {
+			return nil, fmt.Errorf("visualization %s is not supported", visualization)
+		}
+	}
+
+	layout := dashboards.DashboardWidgetLayout{Row: 1, Column: 1, Width: 1, Height: 1}
+	for _, value := range []struct {
+		name  string
+		spec  int
+		field *int
+	}{
+		{"row", s.Layout.Row, &layout.Row},
+		{"column", s.Layout.Column, &layout.Column},
+		{"width", s.Layout.Width, &layout.Width},
+		{"height", s.Layout.Height, &layout.Height},
+	} {
+		if value.spec < 0 {
+			return nil, fmt.Errorf("layout %s can not be negative", value.name)
+		}
+		if value.spec > 0 {
+			*value.field = value.spec
+		}
+	}
+
+	if layout.Column+layout.Width-1 > columns {
+		return nil, fmt.Errorf("does not fit in %d columns", columns)
+	}
+
+	data := &dashboards.DashboardWidget{
+		Visualization: visualization,
+		AccountID:     s.AccountID,
+		Data: []dashboards.DashboardWidgetData{{
+			NRQL:   s.NRQL,
+			Source: s.Source,
+			Facet:  s.Facet,
+		}},
+		Presentation: dashboards.DashboardWidgetPresentation{
+			Title:                s.Title,
+			Notes:                s.Notes,
+			DrilldownDashboardID: s.DrilldownDashboardID,
+		},
+		Layout: layout,
+	}
+
+	if s.Threshold != nil {
+		data.Presentation.Threshold = &dashboards.DashboardWidgetThreshold{}
+		if s.Threshold.Red != nil {
+			data.Presentation.Threshold.Red = *s.Threshold.Red
+		}
+		if s.Threshold.Yellow != nil {
+			data.Presentation.Threshold.Yellow = *s.Threshold.Yellow
+		}
+	}
+
+	return data, nil
+}
+
+// comparableWidgets keeps the widget fields managed by the operator so server side fields do not count as drift
+func comparableWidgets(widgets []dashboards.DashboardWidget) []dashboards.DashboardWidget {
+	result := []dashboards.DashboardWidget{}
+	for _, widget := range widgets {
+		item := dashboards.DashboardWidget{
+			Visualization: widget.Visualization,
+			Data:          []dashboards.DashboardWidgetData{},
+			Presentation:  widget.Presentation,
+			Layout:        widget.Layout,
+		}
+		for _, data := range widget.Data {
+			item.Data = append(item.Data, dashboards.DashboardWidgetData{
+				NRQL:   data.NRQL,
+				Source: data.Source,
+				Facet:  data.Facet,
+			})
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// Adopt takes over an existing dashboard matching importID, or matching the name when adoptExisting is set
+func (s *Dashboard) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Dashboards.GetDashboard(id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
+	}
+
+	if !s.Spec.AdoptExisting {
+		return false
+	}
+
+	items, err := GetClient(ctx).Dashboards.ListDashboards(&dashboards.ListDashboardsParams{Title: s.newRelicName()})
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	ids := []int{}
+	for _, item := range items {
+		if item.Title == s.newRelicName() {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.newRelicName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
+}
+
 // Create in newrelic
 func (s *Dashboard) Create(ctx context.Context) bool {
-	input, err := s.toNewRelic()
+	raw, err := s.rawJSON(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	input, err := s.toNewRelic(raw)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := s.hash(raw)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	rsp, err := client.Dashboards.CreateDashboard(*input)
+	rsp, err := GetClient(ctx).Dashboards.CreateDashboard(*input)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
 	s.Status.Info = "Created"
 	s.Status.SetID(rsp.ID)
-	s.SetFinalizers([]string{finalizer})
+	s.Status.Hash = hash
 	return false
 }
 
@@ -103,7 +543,7 @@ func (s *Dashboard) Delete(ctx context.Context) bool {
 		return false
 	}
 
-	_, err := client.Dashboards.DeleteDashboard(int(*id))
+	_, err := GetClient(ctx).Dashboards.DeleteDashboard(int(*id))
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
@@ -113,15 +553,55 @@ func (s *Dashboard) Delete(ctx context.Context) bool {
 
 // Update object in newrelic
 func (s *Dashboard) Update(ctx context.Context) bool {
-	input, err := s.toNewRelic()
+	raw, err := s.rawJSON(ctx)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	_, err = client.Dashboards.UpdateDashboard(*input)
+	input, err := s.toNewRelic(raw)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := s.hash(raw)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
+	current, err := GetClient(ctx).Dashboards.GetDashboard(input.ID)
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	fields := drift{}
+	fields.check("title", input.Title, current.Title)
+	fields.check("icon", input.Icon, current.Icon)
+	fields.check("visibility", input.Visibility, current.Visibility)
+	fields.check("editable", input.Editable, current.Editable)
+	if input.GridColumnCount != 0 {
+		fields.check("gridColumnCount", input.GridColumnCount, current.GridColumnCount)
+	}
+	fields.check("filter.eventTypes", sortedStrings(input.Filter.EventTypes), sortedStrings(current.Filter.EventTypes))
+	fields.check("filter.attributes", sortedStrings(input.Filter.Attributes), sortedStrings(current.Filter.Attributes))
+	fields.check("widgets", comparableWidgets(input.Widgets), comparableWidgets(current.Widgets))
+
+	if len(fields) > 0 {
+		_, err = GetClient(ctx).Dashboards.UpdateDashboard(*input)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+	}
+
+	if len(fields) > 0 && !s.Status.specChanged(hash) {
+		s.Status.markDrift(ctx, fields)
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
 	return false
 }
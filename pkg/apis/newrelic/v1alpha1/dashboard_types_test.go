@@ -0,0 +1,370 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDashboardLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+	}
+
+	if dashboard.Create(ctx) {
+		t.Fatalf("create failed: %s", dashboard.Status.Info)
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "dashboard" || data.Visibility != dashboards.VisibilityTypes.All {
+		t.Fatalf("unexpected dashboard %+v", data)
+	}
+
+	dashboard.Spec.Icon = string(dashboards.DashboardIconTypes.Bell)
+	if dashboard.Update(ctx) {
+		t.Fatalf("update failed: %s", dashboard.Status.Info)
+	}
+
+	data, err = account.GetDashboard(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Icon != dashboards.DashboardIconTypes.Bell {
+		t.Fatalf("expected icon to be updated, got %s", data.Icon)
+	}
+
+	if dashboard.Delete(ctx) {
+		t.Fatalf("delete failed: %s", dashboard.Status.Info)
+	}
+	if _, err = account.GetDashboard(data.ID); err == nil {
+		t.Fatal("expected dashboard to be deleted")
+	}
+}
+
+func TestDashboardWidgets(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	red := 10.0
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: DashboardSpec{
+			GridColumnCount: 12,
+			Filter:          &DashboardFilter{EventTypes: []string{"Transaction"}, Attributes: []string{"appName"}},
+			Widgets: []DashboardWidget{
+				{Title: "Throughput", Visualization: "line_chart", NRQL: "SELECT rate(count(*), 1 minute) FROM Transaction TIMESERIES", Layout: DashboardWidgetLayout{Width: 6, Height: 3}},
+				{Title: "Errors", Visualization: "billboard", NRQL: "SELECT count(*) FROM TransactionError", Threshold: &DashboardWidgetThreshold{Red: &red}, Layout: DashboardWidgetLayout{Column: 7, Width: 6}},
+				{Visualization: "facet_table", NRQL: "SELECT count(*) FROM Transaction", Facet: "name", Notes: "by transaction", Layout: DashboardWidgetLayout{Width: 12, Height: 4}},
+			},
+		},
+	}
+
+	if dashboard.Create(ctx) {
+		t.Fatalf("create failed: %s", dashboard.Status.Info)
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Widgets) != 3 || data.GridColumnCount != 12 || data.Filter.Attributes[0] != "appName" {
+		t.Fatalf("unexpected dashboard %+v", data)
+	}
+
+	rows := []int{}
+	for _, widget := range data.Widgets {
+		rows = append(rows, widget.Layout.Row)
+	}
+	// the full width table is placed below the three rows of the throughput chart
+	if !reflect.DeepEqual(rows, []int{1, 1, 4}) {
+		t.Fatalf("unexpected widget rows %v", rows)
+	}
+	if data.Widgets[2].Data[0].Facet != "name" || data.Widgets[1].Presentation.Threshold.Red != red {
+		t.Fatalf("unexpected widgets %+v", data.Widgets)
+	}
+
+	// widgets changed in New Relic are repaired
+	data.Widgets = data.Widgets[:1]
+	if _, err = account.UpdateDashboard(*data); err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.Update(ctx) {
+		t.Fatalf("update failed: %s", dashboard.Status.Info)
+	}
+	if !dashboard.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatal("expected widget drift to be detected")
+	}
+
+	data, err = account.GetDashboard(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Widgets) != 3 {
+		t.Fatalf("expected widgets to be restored, got %d", len(data.Widgets))
+	}
+}
+
+// failingDashboards rejects every dashboard update
+type failingDashboards struct {
+	newrelic.Dashboards
+}
+
+func (failingDashboards) UpdateDashboard(dashboards.Dashboard) (*dashboards.Dashboard, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestDashboardUpdateFailureNotDrift(t *testing.T) {
+	account := fake.NewAccount()
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: DashboardSpec{
+			Widgets: []DashboardWidget{{Visualization: "markdown", Source: "# hi"}},
+		},
+	}
+	if dashboard.Create(WithClient(context.TODO(), account.Client())) {
+		t.Fatalf("create failed: %s", dashboard.Status.Info)
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.Widgets = nil
+	if _, err = account.UpdateDashboard(*data); err != nil {
+		t.Fatal(err)
+	}
+
+	client := account.Client()
+	client.Dashboards = failingDashboards{client.Dashboards}
+	recorder := record.NewFakeRecorder(10)
+	ctx := WithRecorder(WithClient(context.TODO(), client), recorder, dashboard)
+
+	// the drift is only reported once it has been repaired
+	if !dashboard.Update(ctx) {
+		t.Fatal("expected update to fail")
+	}
+	if dashboard.Status.IsConditionTrue(ConditionDrifted) || len(recorder.Events) != 0 {
+		t.Fatalf("expected no drift to be reported, got %+v", dashboard.Status.Conditions)
+	}
+}
+
+func TestDashboardWidgetPlacement(t *testing.T) {
+	widget := func(width int, layout DashboardWidgetLayout) DashboardWidget {
+		layout.Width = width
+		return DashboardWidget{Visualization: "billboard", NRQL: "SELECT count(*) FROM Transaction", Layout: layout}
+	}
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: DashboardSpec{Widgets: []DashboardWidget{
+			widget(0, DashboardWidgetLayout{}),
+			widget(0, DashboardWidgetLayout{Row: 1, Column: 2}),
+			widget(2, DashboardWidgetLayout{}),
+			widget(0, DashboardWidgetLayout{}),
+			widget(0, DashboardWidgetLayout{}),
+			widget(3, DashboardWidgetLayout{}),
+		}},
+	}
+
+	data, err := dashboard.toNewRelic("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	positions := [][2]int{}
+	for _, item := range data.Widgets {
+		positions = append(positions, [2]int{item.Layout.Row, item.Layout.Column})
+	}
+	// three columns, the placed widget keeps its cell and the others fill the rows around it
+	expected := [][2]int{{1, 1}, {1, 2}, {2, 1}, {1, 3}, {2, 3}, {3, 1}}
+	if !reflect.DeepEqual(positions, expected) {
+		t.Fatalf("expected widgets at %v, got %v", expected, positions)
+	}
+}
+
+func TestDashboardInvalidWidgets(t *testing.T) {
+	tests := map[string]DashboardSpec{
+		"missing nrql":       {Widgets: []DashboardWidget{{Visualization: "line_chart"}}},
+		"markdown with nrql": {Widgets: []DashboardWidget{{Visualization: "markdown", Source: "# hi", NRQL: "SELECT 1"}}},
+		"unsupported":        {Widgets: []DashboardWidget{{Visualization: "metric_line_chart", NRQL: "SELECT 1"}}},
+		"too wide":           {Widgets: []DashboardWidget{{Visualization: "billboard", NRQL: "SELECT 1", Layout: DashboardWidgetLayout{Column: 2, Width: 3}}}},
+		"attribute filter":   {Filter: &DashboardFilter{Attributes: []string{"appName"}}},
+	}
+
+	for name, spec := range tests {
+		dashboard := &Dashboard{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec:       spec,
+		}
+
+		if !dashboard.Create(WithClient(context.TODO(), fake.New())) {
+			t.Fatalf("%s: expected create to fail", name)
+		}
+		if condition := dashboard.Status.GetCondition(ConditionReady); condition == nil || condition.Reason != ReasonInvalidSpec {
+			t.Fatalf("%s: expected invalid spec, got %+v", name, condition)
+		}
+	}
+}
+
+const exportedDashboard = `{"dashboard": {
+	"id": 99,
+	"title": "exported",
+	"icon": "bar-chart",
+	"grid_column_count": 12,
+	"filter": {"event_types": ["Transaction"]},
+	"widgets": [
+		{"widget_id": 5, "visualization": "billboard", "data": [{"nrql": "SELECT count(*) FROM Transaction"}], "layout": {"row": 1, "column": 1, "width": 4, "height": 3}}
+	]
+}}`
+
+func TestDashboardJSON(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       DashboardSpec{JSON: exportedDashboard},
+	}
+
+	if dashboard.Create(ctx) {
+		t.Fatalf("create failed: %s", dashboard.Status.Info)
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "dashboard" || data.Icon != dashboards.DashboardIconTypes.BarChart || data.GridColumnCount != 12 {
+		t.Fatalf("unexpected dashboard %+v", data)
+	}
+	if len(data.Widgets) != 1 || data.Widgets[0].Layout.Width != 4 || data.Widgets[0].ID == 5 {
+		t.Fatalf("unexpected widgets %+v", data.Widgets)
+	}
+
+	dashboard.Spec.Widgets = []DashboardWidget{{Visualization: "markdown", Source: "# hi"}}
+	if !dashboard.Update(ctx) {
+		t.Fatal("expected json combined with widgets to fail")
+	}
+}
+
+func TestDashboardJSONFromConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboards", Namespace: "team"},
+		Data:       map[string]string{"overview.json": exportedDashboard},
+	}
+	kube := clientfake.NewFakeClientWithScheme(scheme.Scheme, configMap)
+
+	account := fake.NewAccount()
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "team"},
+		Spec: DashboardSpec{JSONFrom: &DashboardJSONSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "dashboards"},
+				Key:                  "missing.json",
+			},
+		}},
+	}
+
+	if !DoReconcile(ctx, L, dashboard).Requeue {
+		t.Fatal("expected a missing key to fail")
+	}
+	if condition := dashboard.Status.GetCondition(ConditionDependenciesResolved); condition == nil || condition.Reason != ReasonDependencyNotFound {
+		t.Fatalf("expected missing dependency, got %+v", condition)
+	}
+
+	dashboard.Spec.JSONFrom.ConfigMapKeyRef.Key = "overview.json"
+	if DoReconcile(ctx, L, dashboard).Requeue {
+		t.Fatalf("unexpected requeue: %s", dashboard.Status.Info)
+	}
+
+	// an edit to the ConfigMap is applied and not reported as drift
+	configMap.Data["overview.json"] = strings.Replace(exportedDashboard, `"width": 4`, `"width": 6`, 1)
+	if err := kube.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if DoReconcile(ctx, L, dashboard).Requeue {
+		t.Fatalf("unexpected requeue: %s", dashboard.Status.Info)
+	}
+	if dashboard.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatal("expected a ConfigMap change to not be drift")
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Widgets[0].Layout.Width != 6 {
+		t.Fatalf("expected the ConfigMap change to be applied, got %+v", data.Widgets[0].Layout)
+	}
+
+	// an optional source that goes away leaves the dashboard alone
+	optional := true
+	dashboard.Spec.JSONFrom.ConfigMapKeyRef.Optional = &optional
+	dashboard.Spec.JSONFrom.ConfigMapKeyRef.Key = "removed.json"
+	if !DoReconcile(ctx, L, dashboard).Requeue {
+		t.Fatal("expected a missing optional key to fail")
+	}
+	if condition := dashboard.Status.GetCondition(ConditionDependenciesResolved); condition == nil || condition.Reason != ReasonDependencyNotFound {
+		t.Fatalf("expected missing dependency, got %+v", condition)
+	}
+	data, err = account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Widgets) == 0 {
+		t.Fatal("expected the widgets to be kept")
+	}
+}
+
+func TestDashboardTitle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	dashboard := &Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-overview"},
+		Spec:       DashboardSpec{Title: "Team Overview"},
+	}
+
+	if dashboard.Create(ctx) {
+		t.Fatalf("create failed: %s", dashboard.Status.Info)
+	}
+
+	data, err := account.GetDashboard(*dashboard.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "Team Overview" {
+		t.Fatalf("expected the dashboard to be titled by spec.title, got %s", data.Title)
+	}
+
+	dashboard.Spec.Title = ""
+	if dashboard.Update(ctx) {
+		t.Fatalf("update failed: %s", dashboard.Status.Info)
+	}
+
+	data, err = account.GetDashboard(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Title != "team-overview" {
+		t.Fatalf("expected the title to fall back to the resource name, got %s", data.Title)
+	}
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConditionDrifted is true when the New Relic object was changed outside of the operator and had to be repaired
+const ConditionDrifted ConditionType = "Drifted"
+
+// Reasons set on the drifted condition
+const (
+	ReasonInSync        = "InSync"
+	ReasonDriftRepaired = "DriftRepaired"
+	ReasonDeletedRemote = "DeletedInNewRelic"
+)
+
+// drift is the list of fields that differ between the desired and live object
+type drift []string
+
+// check records field when the desired and live values differ
+func (d *drift) check(field string, desired interface{}, live interface{}) {
+	if !reflect.DeepEqual(desired, live) {
+		*d = append(*d, field)
+	}
+}
+
+// hashSpec returns a hash of the desired spec
+func hashSpec(spec interface{}) ([]byte, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// specChanged reports if hash differs from the last hash applied to New Relic
+func (s *Status) specChanged(hash []byte) bool {
+	return !reflect.DeepEqual(s.Hash, hash)
+}
+
+// markDrift records that the live object differed from the last applied spec
+func (s *Status) markDrift(ctx context.Context, fields drift) {
+	message := fmt.Sprintf("repairing changes made outside of the operator to %s", strings.Join(fields, ", "))
+	GetLogger(ctx).Info(message)
+	s.SetCondition(ConditionDrifted, corev1.ConditionTrue, ReasonDriftRepaired, message)
+	recordEvent(ctx, corev1.EventTypeWarning, ReasonDriftRepaired, message)
+}
+
+// markDeletedRemote records that the object no longer exists in New Relic and will be recreated
+func (s *Status) markDeletedRemote(ctx context.Context) {
+	message := fmt.Sprintf("object %s was deleted outside of the operator, recreating it", *s.ID)
+	GetLogger(ctx).Info(message)
+	s.SetCondition(ConditionDrifted, corev1.ConditionTrue, ReasonDeletedRemote, message)
+	recordEvent(ctx, corev1.EventTypeWarning, ReasonDeletedRemote, message)
+	s.ID = nil
+	s.Hash = nil
+}
+
+// markInSync records that the live object matches the spec
+func (s *Status) markInSync() {
+	s.SetCondition(ConditionDrifted, corev1.ConditionFalse, ReasonInSync, "")
+}
+
+// sortedStrings returns a sorted copy so ordering does not count as drift
+func sortedStrings(items []string) []string {
+	result := append([]string{}, items...)
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicy decides what happens to the New Relic object when the resource is deleted
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the object from New Relic, this is the default
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the object in New Relic
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// hasFinalizer reports if the operator finalizer is set on obj
+func hasFinalizer(obj metav1.Object) bool {
+	for _, item := range obj.GetFinalizers() {
+		if item == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// addFinalizer adds the operator finalizer to obj leaving other finalizers in place
+func addFinalizer(obj metav1.Object) {
+	if !hasFinalizer(obj) {
+		obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	}
+}
+
+// removeFinalizer removes the operator finalizer from obj leaving other finalizers in place
+func removeFinalizer(obj metav1.Object) {
+	finalizers := []string{}
+	for _, item := range obj.GetFinalizers() {
+		if item != finalizer {
+			finalizers = append(finalizers, item)
+		}
+	}
+	obj.SetFinalizers(finalizers)
+}
@@ -2,9 +2,15 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -41,36 +47,156 @@ var DefaultRequeue = reconcile.Result{
 	RequeueAfter: time.Minute * 5,
 }
 
-// DoReconcile generic processing loop
-func DoReconcile(log logr.Logger, instance CRD) reconcile.Result {
+// DriftInterval is how often synced objects are compared with New Relic again,
+// changes made outside of the operator are repaired within it
+var DriftInterval = time.Minute * 10
+
+// DoReconcile generic processing loop, ctx must carry the New Relic client for the instance
+func DoReconcile(ctx context.Context, log logr.Logger, instance CRD) reconcile.Result {
 	reconcileResult := reconcile.Result{}
+	status := instance.GetStatus()
 
 	if instance.GetDeletionTimestamp() != nil {
+		if !hasFinalizer(instance) {
+			// already cleaned up, only foreign finalizers are left
+			return reconcileResult
+		}
+
 		log = log.WithValues("action", "delete")
-		ctx := WithLogger(context.TODO(), &log)
+		ctx := WithLogger(ctx, &log)
 
 		log.Info("")
-		if instance.Delete(ctx) {
+		if instance.GetDeletionPolicy() == DeletionPolicyOrphan {
+			log.Info("leaving object in New Relic", "deletionPolicy", DeletionPolicyOrphan)
+			removeFinalizer(instance)
+			status.markDeleting()
+		} else if instance.Delete(ctx) {
 			reconcileResult = DefaultRequeue
 		} else {
-			instance.SetFinalizers(nil)
-		}
-	} else if instance.IsCreated() {
-		log = log.WithValues("action", "update")
-		ctx := WithLogger(context.TODO(), &log)
-
-		log.Info("")
-		if instance.Update(ctx) {
-			reconcileResult = DefaultRequeue
+			removeFinalizer(instance)
+			status.markDeleting()
 		}
 	} else {
-		log = log.WithValues("action", "create")
-		ctx := WithLogger(context.TODO(), &log)
+		// added on every pass and before creating, so an object in New Relic is never left without
+		// the finalizer when an earlier write of it failed
+		addFinalizer(instance)
 
-		log.Info("")
-		if instance.Create(ctx) {
-			reconcileResult = DefaultRequeue
+		if instance.IsCreated() {
+			log = log.WithValues("action", "update")
+			ctx := WithLogger(ctx, &log)
+
+			log.Info("")
+			if instance.Update(ctx) {
+				reconcileResult = DefaultRequeue
+			} else {
+				status.markSynced()
+				reconcileResult = reconcile.Result{RequeueAfter: DriftInterval}
+			}
+		} else {
+			log = log.WithValues("action", "create")
+			ctx := WithLogger(ctx, &log)
+
+			log.Info("")
+			failed := instance.Adopt(ctx)
+			if !failed && instance.IsCreated() {
+				// an existing object was adopted, bring it in line with the spec
+				failed = instance.Update(ctx)
+			} else if !failed {
+				failed = instance.Create(ctx)
+			}
+
+			if failed {
+				reconcileResult = DefaultRequeue
+			} else {
+				status.markSynced()
+				reconcileResult = reconcile.Result{RequeueAfter: DriftInterval}
+			}
 		}
 	}
+
+	status.observe(instance.GetGeneration())
 	return reconcileResult
 }
+
+// Persist writes the changes made to instance since original back to the cluster.
+// Finalizers go through a merge patch and status through the status subresource so
+// concurrent edits to the spec are never overwritten. Finalizers are written first, the
+// status is written even when that fails so the ID of an object created in New Relic is kept.
+func Persist(ctx context.Context, c client.Client, original CRD, instance CRD) error {
+	key := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetName()}
+
+	finalizerErr := persistFinalizers(ctx, c, key, original, instance)
+	if errors.IsNotFound(finalizerErr) {
+		return nil
+	}
+
+	if !reflect.DeepEqual(original.GetStatus(), instance.GetStatus()) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := empty(instance)
+			if err := c.Get(ctx, key, latest); err != nil {
+				return err
+			}
+
+			*latest.GetStatus() = *instance.GetStatus()
+			return c.Status().Update(ctx, latest)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return finalizerErr
+}
+
+// persistFinalizers adds and removes the finalizers changed since original on the stored object
+func persistFinalizers(ctx context.Context, c client.Client, key types.NamespacedName, original CRD, instance CRD) error {
+	added := missing(instance.GetFinalizers(), original.GetFinalizers())
+	removed := missing(original.GetFinalizers(), instance.GetFinalizers())
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	latest := empty(instance)
+	if err := c.Get(ctx, key, latest); err != nil {
+		return err
+	}
+
+	finalizers := append(missing(latest.GetFinalizers(), removed), missing(added, latest.GetFinalizers())...)
+	if reflect.DeepEqual(finalizers, latest.GetFinalizers()) {
+		return nil
+	}
+	return c.Patch(ctx, latest, finalizerPatch(finalizers))
+}
+
+// empty returns a new object of the same kind as instance
+func empty(instance CRD) CRD {
+	return reflect.New(reflect.TypeOf(instance).Elem()).Interface().(CRD)
+}
+
+// finalizerPatch replaces the finalizers. It carries no resource version, the cached object
+// it is computed from lags behind writes and would make the server reject it as a conflict.
+func finalizerPatch(finalizers []string) client.Patch {
+	data, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": finalizers,
+		},
+	})
+	return client.ConstantPatch(types.MergePatchType, data)
+}
+
+// missing returns the items of a that are not in b
+func missing(a []string, b []string) []string {
+	result := []string{}
+	for _, item := range a {
+		found := false
+		for _, other := range b {
+			if item == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+	return result
+}
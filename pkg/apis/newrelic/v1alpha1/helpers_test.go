@@ -0,0 +1,380 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDoReconcileReady(t *testing.T) {
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Generation: 3},
+	}
+
+	result := DoReconcile(WithClient(context.TODO(), fake.New()), L, policy)
+	if result.Requeue {
+		t.Fatalf("unexpected requeue: %s", policy.Status.Info)
+	}
+
+	status := policy.Status
+	if status.Phase != PhaseReady || status.ObservedGeneration != 3 || status.LastSyncTime == nil {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	for _, conditionType := range []ConditionType{ConditionReady, ConditionSynced, ConditionDependenciesResolved} {
+		if !status.IsConditionTrue(conditionType) {
+			t.Fatalf("expected %s to be true", conditionType)
+		}
+		if status.GetCondition(conditionType).ObservedGeneration != 3 {
+			t.Fatalf("expected %s to observe generation 3", conditionType)
+		}
+	}
+}
+
+func TestDoReconcileRequeuesForDrift(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Generation: 1},
+		Spec: AlertPolicySpec{
+			IncidentPreference: string(alerts.IncidentPreferenceTypes.PerPolicy),
+		},
+	}
+
+	result := DoReconcile(ctx, L, policy)
+	if result.RequeueAfter != DriftInterval {
+		t.Fatalf("expected a requeue after %s, got %+v", DriftInterval, result)
+	}
+
+	data, err := account.GetPolicy(*policy.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.IncidentPreference = alerts.IncidentPreferenceTypes.PerConditionAndTarget
+	if _, err = account.UpdatePolicy(*data); err != nil {
+		t.Fatal(err)
+	}
+
+	// the requeue syncs the unchanged generation again
+	result = DoReconcile(ctx, L, policy)
+	if result.RequeueAfter != DriftInterval {
+		t.Fatalf("expected a requeue after %s, got %+v", DriftInterval, result)
+	}
+	data, err = account.GetPolicy(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.IncidentPreference != alerts.IncidentPreferenceTypes.PerPolicy {
+		t.Fatalf("expected drift to be repaired, got %s", data.IncidentPreference)
+	}
+}
+
+func TestDoReconcileInvalidSpec(t *testing.T) {
+	channel := &AlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "channel"},
+	}
+
+	result := DoReconcile(WithClient(context.TODO(), fake.New()), L, channel)
+	if !result.Requeue {
+		t.Fatal("expected requeue")
+	}
+
+	synced := channel.Status.GetCondition(ConditionSynced)
+	if synced == nil || synced.Status != corev1.ConditionFalse || synced.Reason != ReasonInvalidSpec {
+		t.Fatalf("unexpected synced condition %+v", synced)
+	}
+	if channel.Status.Phase != PhaseFailed {
+		t.Fatalf("unexpected phase %s", channel.Status.Phase)
+	}
+}
+
+func TestDoReconcileMissingDependency(t *testing.T) {
+	account := fake.NewAccount()
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec: MonitorSpec{
+			Conditions: []Conditions{{PolicyName: "policy"}},
+		},
+	}
+	monitor.Default()
+
+	DoReconcile(WithClient(context.TODO(), account.Client()), L, monitor)
+	resolved := monitor.Status.GetCondition(ConditionDependenciesResolved)
+	if resolved == nil || resolved.Status != corev1.ConditionFalse || resolved.Reason != ReasonDependencyNotFound {
+		t.Fatalf("unexpected dependencies condition %+v", resolved)
+	}
+	transition := resolved.LastTransitionTime
+
+	if _, err := account.CreatePolicy(alerts.Policy{Name: "policy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	DoReconcile(WithClient(context.TODO(), account.Client()), L, monitor)
+	if !monitor.Status.IsConditionTrue(ConditionDependenciesResolved) || !monitor.Status.IsConditionTrue(ConditionReady) {
+		t.Fatalf("expected monitor to become ready, got %+v", monitor.Status.Conditions)
+	}
+	if monitor.Status.GetCondition(ConditionDependenciesResolved).LastTransitionTime.Before(&transition) {
+		t.Fatal("expected transition time to move forward")
+	}
+}
+
+func TestPersistConcurrentEdit(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Namespace: "team", Name: "policy"}
+	c := clientfake.NewFakeClientWithScheme(s, &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, Finalizers: []string{"other"}},
+	})
+
+	instance := &AlertPolicy{}
+	if err := c.Get(context.TODO(), key, instance); err != nil {
+		t.Fatal(err)
+	}
+	original := instance.DeepCopy()
+
+	// a user edits the spec while the reconcile is in progress
+	edited := instance.DeepCopy()
+	edited.Spec.IncidentPreference = string(alerts.IncidentPreferenceTypes.PerPolicy)
+	if err := c.Update(context.TODO(), edited); err != nil {
+		t.Fatal(err)
+	}
+
+	DoReconcile(WithClient(context.TODO(), fake.New()), L, instance)
+	if err := Persist(context.TODO(), c, original, instance); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &AlertPolicy{}
+	if err := c.Get(context.TODO(), key, result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Spec.IncidentPreference != edited.Spec.IncidentPreference {
+		t.Fatalf("expected the concurrent spec edit to be kept, got %q", result.Spec.IncidentPreference)
+	}
+	if !result.IsCreated() || result.Status.Phase != PhaseReady {
+		t.Fatalf("expected status to be written, got %+v", result.Status)
+	}
+	if len(result.Finalizers) != 2 || result.Finalizers[0] != "other" || result.Finalizers[1] != finalizer {
+		t.Fatalf("expected finalizer to be added, got %v", result.Finalizers)
+	}
+}
+
+// staleClient reads from a cache that lags behind the writes and rejects patches with an outdated resource version like the API server
+type staleClient struct {
+	client.Client
+	cache client.Client
+}
+
+func (c *staleClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.cache.Get(ctx, key, obj)
+}
+
+func (c *staleClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	sent := &AlertPolicy{}
+	if err := json.Unmarshal(data, sent); err != nil {
+		return err
+	}
+
+	stored := &AlertPolicy{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: sent.Namespace, Name: obj.(*AlertPolicy).Name}, stored); err != nil {
+		return err
+	}
+	if sent.ResourceVersion != "" && sent.ResourceVersion != stored.ResourceVersion {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "alertpolicies"}, stored.Name, errors.New("stale"))
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Status only writes the status of the stored object like the status subresource
+func (c *staleClient) Status() client.StatusWriter {
+	return staleStatusWriter{c.Client}
+}
+
+type staleStatusWriter struct {
+	client.Client
+}
+
+func (w staleStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	instance := obj.(*AlertPolicy)
+	stored := &AlertPolicy{}
+	if err := w.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, stored); err != nil {
+		return err
+	}
+	stored.Status = instance.Status
+	return w.Client.Update(ctx, stored, opts...)
+}
+
+func (w staleStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return errors.New("not implemented")
+}
+
+func TestPersistStaleCache(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Namespace: "team", Name: "policy"}
+	seed := &AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, ResourceVersion: "1"}}
+	c := &staleClient{
+		Client: clientfake.NewFakeClientWithScheme(s, seed.DeepCopy()),
+		cache:  clientfake.NewFakeClientWithScheme(s, seed.DeepCopy()),
+	}
+
+	// the object changed since the cache saw it
+	latest := &AlertPolicy{}
+	if err := c.Client.Get(context.TODO(), key, latest); err != nil {
+		t.Fatal(err)
+	}
+	latest.Labels = map[string]string{"team": "a"}
+	if err := c.Client.Update(context.TODO(), latest); err != nil {
+		t.Fatal(err)
+	}
+
+	instance := &AlertPolicy{}
+	if err := c.Get(context.TODO(), key, instance); err != nil {
+		t.Fatal(err)
+	}
+	original := instance.DeepCopy()
+	DoReconcile(WithClient(context.TODO(), fake.New()), L, instance)
+	if err := Persist(context.TODO(), c, original, instance); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &AlertPolicy{}
+	if err := c.Client.Get(context.TODO(), key, result); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFinalizer(result) || !result.IsCreated() {
+		t.Fatalf("expected finalizer and status to be written, got %v %+v", result.Finalizers, result.Status)
+	}
+}
+
+// failingPatchClient fails the first finalizer patch
+type failingPatchClient struct {
+	client.Client
+	failed bool
+}
+
+func (c *failingPatchClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if !c.failed {
+		c.failed = true
+		return errors.New("patch failed")
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestDoReconcileAddsMissingFinalizer(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Namespace: "team", Name: "policy"}
+	c := &failingPatchClient{Client: clientfake.NewFakeClientWithScheme(s, &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+	})}
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	sync := func() error {
+		instance := &AlertPolicy{}
+		if err := c.Get(context.TODO(), key, instance); err != nil {
+			t.Fatal(err)
+		}
+		original := instance.DeepCopy()
+		DoReconcile(ctx, L, instance)
+		return Persist(context.TODO(), c, original, instance)
+	}
+
+	// the policy is created but writing the finalizer fails
+	if err := sync(); err == nil {
+		t.Fatal("expected the finalizer write to fail")
+	}
+	result := &AlertPolicy{}
+	if err := c.Get(context.TODO(), key, result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsCreated() || hasFinalizer(result) {
+		t.Fatalf("expected only the status to be written, got %+v %v", result.Status, result.Finalizers)
+	}
+
+	if err := sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), key, result); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFinalizer(result) {
+		t.Fatalf("expected the finalizer to be added by the next sync, got %v", result.Finalizers)
+	}
+	if _, err := account.GetPolicy(*result.Status.GetID()); err != nil {
+		t.Fatalf("expected the policy to be kept: %s", err)
+	}
+}
+
+func TestDoReconcileDeleteKeepsForeignFinalizers(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Finalizers: []string{"other"}},
+	}
+	DoReconcile(ctx, L, policy)
+	if len(policy.Finalizers) != 2 {
+		t.Fatalf("expected finalizer to be added, got %v", policy.Finalizers)
+	}
+
+	now := metav1.Now()
+	policy.DeletionTimestamp = &now
+	if DoReconcile(ctx, L, policy).Requeue {
+		t.Fatalf("unexpected requeue: %s", policy.Status.Info)
+	}
+	if len(policy.Finalizers) != 1 || policy.Finalizers[0] != "other" {
+		t.Fatalf("expected only the foreign finalizer to remain, got %v", policy.Finalizers)
+	}
+	if _, err := account.GetPolicy(*policy.Status.GetID()); err == nil {
+		t.Fatal("expected policy to be deleted")
+	}
+}
+
+func TestDoReconcileDeleteOrphan(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy := &AlertPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec:       AlertPolicySpec{DeletionPolicy: DeletionPolicyOrphan},
+	}
+	DoReconcile(ctx, L, policy)
+
+	now := metav1.Now()
+	policy.DeletionTimestamp = &now
+	if DoReconcile(ctx, L, policy).Requeue {
+		t.Fatalf("unexpected requeue: %s", policy.Status.Info)
+	}
+	if len(policy.Finalizers) != 0 {
+		t.Fatalf("expected finalizer to be removed, got %v", policy.Finalizers)
+	}
+	if _, err := account.GetPolicy(*policy.Status.GetID()); err != nil {
+		t.Fatalf("expected policy to be kept: %s", err)
+	}
+}
@@ -0,0 +1,391 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Infrastructure condition types
+const (
+	InfraMetric           = "infra_metric"
+	InfraProcessRunning   = "infra_process_running"
+	InfraHostNotReporting = "infra_host_not_reporting"
+)
+
+// InfraAlertConditionSpec defines the desired state of InfraAlertCondition
+type InfraAlertConditionSpec struct {
+	// PolicyName is the name of the New Relic policy the condition belongs to
+	PolicyName string `json:"policyName,omitempty"`
+	// PolicyRef is the AlertPolicy resource the condition belongs to, it takes precedence over policyName
+	PolicyRef *ObjectReference `json:"policyRef,omitempty"`
+	// Type defaults to infra_metric
+	// +kubebuilder:validation:Enum=infra_metric;infra_process_running;infra_host_not_reporting
+	Type string `json:"type,omitempty"`
+	// Event is the sample the value is read from such as SystemSample or StorageSample, it is required for infra_metric
+	Event string `json:"event,omitempty"`
+	// Select is the attribute of the event compared with the thresholds such as cpuPercent, it is required for infra_metric
+	Select string `json:"select,omitempty"`
+	// Comparison of the value with the thresholds, defaults to above
+	// +kubebuilder:validation:Enum=above;below;equal
+	Comparison string `json:"comparison,omitempty"`
+	// Where filters the hosts the condition applies to
+	Where string `json:"where,omitempty"`
+	// ProcessWhere selects the processes counted by infra_process_running
+	ProcessWhere string `json:"processWhere,omitempty"`
+	// IntegrationProvider selects the cloud integration the event is reported by
+	IntegrationProvider string `json:"integrationProvider,omitempty"`
+	// Applications limits the condition to hosts running these APM applications
+	Applications []string `json:"applications,omitempty"`
+	// Critical opens a critical violation
+	Critical InfraAlertThreshold `json:"critical"`
+	// Warning opens a warning violation
+	Warning *InfraAlertThreshold `json:"warning,omitempty"`
+	// ViolationCloseTimerHours closes violations that stay open longer
+	// +kubebuilder:validation:Enum=1;2;4;8;12;24
+	ViolationCloseTimerHours int    `json:"violationCloseTimerHours,omitempty"`
+	RunbookURL               string `json:"runbookURL,omitempty"`
+	// Enabled defaults to true
+	Enabled    *bool                        `json:"enabled,omitempty"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// InfraAlertThreshold opens a violation when the value crosses it
+type InfraAlertThreshold struct {
+	// Value is not used by infra_host_not_reporting
+	// +kubebuilder:validation:Type=number
+	Value float64 `json:"value,omitempty"`
+	// DurationMinutes the threshold has to be crossed for
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	DurationMinutes int `json:"durationMinutes"`
+	// TimeFunction all requires every value in the duration to cross the threshold, any only one, defaults to all
+	// +kubebuilder:validation:Enum=all;any
+	TimeFunction string `json:"timeFunction,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InfraAlertCondition is the Schema for the infraalertconditions API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=infraalertconditions,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type InfraAlertCondition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              InfraAlertConditionSpec `json:"spec"`
+	Status            Status                  `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InfraAlertConditionList contains a list of InfraAlertCondition
+type InfraAlertConditionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []InfraAlertCondition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InfraAlertCondition{}, &InfraAlertConditionList{})
+}
+
+// Additional Code
+
+var _ CRD = &InfraAlertCondition{}
+
+// IsCreated specifies if the object has been created in new relic yet
+func (s *InfraAlertCondition) IsCreated() bool {
+	return s.Status.IsCreated()
+}
+
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *InfraAlertCondition) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *InfraAlertCondition) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *InfraAlertCondition) GetStatus() *Status {
+	return &s.Status
+}
+
+func (s *InfraAlertCondition) policyID(ctx context.Context) (int, error) {
+	return resolvePolicyID(ctx, s, s.Spec.PolicyName, s.Spec.PolicyRef)
+}
+
+// UsesAlertPolicy reports if the condition refers to the AlertPolicy resource namespace/name
+func (s *InfraAlertCondition) UsesAlertPolicy(namespace string, name string) bool {
+	return s.Spec.PolicyRef != nil && s.Spec.PolicyRef.refersTo(s.GetNamespace(), namespace, name)
+}
+
+// where adds a filter on the apmApplicationNames attribute, which lists the applications of a host as |name|name|
+func (s *InfraAlertCondition) where() string {
+	if len(s.Spec.Applications) == 0 {
+		return s.Spec.Where
+	}
+
+	applications := []string{}
+	for _, name := range s.Spec.Applications {
+		name = strings.ReplaceAll(name, "'", "\\'")
+		applications = append(applications, fmt.Sprintf("apmApplicationNames LIKE '%%|%s|%%'", name))
+	}
+
+	where := "(" + strings.Join(applications, " OR ") + ")"
+	if s.Spec.Where != "" {
+		where = "(" + s.Spec.Where + ") AND " + where
+	}
+	return where
+}
+
+func (s *InfraAlertCondition) toNewRelic(policyID int) (*alerts.InfrastructureCondition, error) {
+	conditionType := s.Spec.Type
+	if conditionType == "" {
+		conditionType = InfraMetric
+	}
+
+	if conditionType == InfraMetric && (s.Spec.Event == "" || s.Spec.Select == "") {
+		return nil, errors.New("event and select are required for infra_metric")
+	}
+	if conditionType != InfraProcessRunning && s.Spec.ProcessWhere != "" {
+		return nil, errors.New("processWhere can only be set for infra_process_running")
+	}
+
+	data := alerts.InfrastructureCondition{
+		Name:                s.GetName(),
+		PolicyID:            policyID,
+		Type:                conditionType,
+		Where:               s.where(),
+		ProcessWhere:        s.Spec.ProcessWhere,
+		IntegrationProvider: s.Spec.IntegrationProvider,
+		RunbookURL:          s.Spec.RunbookURL,
+		Enabled:             s.Spec.Enabled == nil || *s.Spec.Enabled,
+		Critical:            s.Spec.Critical.toNewRelic(),
+	}
+
+	if conditionType == InfraMetric {
+		data.Event = s.Spec.Event
+		data.Select = s.Spec.Select
+	}
+
+	if conditionType != InfraHostNotReporting {
+		data.Comparison = string(alerts.OperatorTypes.Above)
+		if s.Spec.Comparison != "" {
+			data.Comparison = s.Spec.Comparison
+		}
+	}
+
+	if s.Spec.Warning != nil {
+		data.Warning = s.Spec.Warning.toNewRelic()
+	}
+
+	if s.Spec.ViolationCloseTimerHours != 0 {
+		timer := s.Spec.ViolationCloseTimerHours
+		data.ViolationCloseTimer = &timer
+	}
+
+	if s.Status.ID != nil {
+		data.ID = int(*s.Status.GetID())
+	}
+	return &data, nil
+}
+
+func (t InfraAlertThreshold) toNewRelic() *alerts.InfrastructureConditionThreshold {
+	threshold := &alerts.InfrastructureConditionThreshold{
+		Duration: t.DurationMinutes,
+		Function: string(alerts.TimeFunctionTypes.All),
+		Value:    t.Value,
+	}
+
+	if t.TimeFunction != "" {
+		threshold.Function = t.TimeFunction
+	}
+	return threshold
+}
+
+// Adopt takes over an existing condition matching importID, or of the policy matching the name when adoptExisting is set
+func (s *InfraAlertCondition) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Alerts.GetInfrastructureCondition(id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
+	}
+
+	if !s.Spec.AdoptExisting {
+		return false
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	items, err := GetClient(ctx).Alerts.ListInfrastructureConditions(policyID)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	ids := []int{}
+	for _, item := range items {
+		if item.Name == s.GetName() {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.GetName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
+}
+
+// Create in newrelic
+func (s *InfraAlertCondition) Create(ctx context.Context) bool {
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	input, err := s.toNewRelic(policyID)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	data, err := GetClient(ctx).Alerts.CreateInfrastructureCondition(*input)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	s.Status.SetID(data.ID)
+	s.Status.Hash = hash
+	return false
+}
+
+// Delete in newrelic
+func (s *InfraAlertCondition) Delete(ctx context.Context) bool {
+	logger := GetLogger(ctx)
+
+	id := s.Status.GetID()
+	if id == nil {
+		logger.Info("object does not exist")
+		return false
+	}
+
+	// conditions are removed along with their policy
+	err := GetClient(ctx).Alerts.DeleteInfrastructureCondition(*id)
+	if isNotFound(err) {
+		return false
+	}
+	return s.Status.HandleOnError(ctx, err)
+}
+
+// Update object in newrelic
+func (s *InfraAlertCondition) Update(ctx context.Context) bool {
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	input, err := s.toNewRelic(policyID)
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	current, err := GetClient(ctx).Alerts.GetInfrastructureCondition(input.ID)
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if current.PolicyID != policyID {
+		// conditions can not be moved to another policy
+		GetLogger(ctx).Info("moving condition to another policy", "policy", policyID)
+		err = GetClient(ctx).Alerts.DeleteInfrastructureCondition(input.ID)
+		if !isNotFound(err) && s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+		s.Status.ID = nil
+		return s.Create(ctx)
+	}
+
+	fields := drift{}
+	fields.check("name", input.Name, current.Name)
+	fields.check("type", input.Type, current.Type)
+	fields.check("event", input.Event, current.Event)
+	fields.check("select", input.Select, current.Select)
+	fields.check("comparison", input.Comparison, current.Comparison)
+	fields.check("where", input.Where, current.Where)
+	fields.check("processWhere", input.ProcessWhere, current.ProcessWhere)
+	fields.check("integrationProvider", input.IntegrationProvider, current.IntegrationProvider)
+	fields.check("critical", input.Critical, current.Critical)
+	fields.check("warning", input.Warning, current.Warning)
+	if input.ViolationCloseTimer != nil {
+		fields.check("violationCloseTimerHours", input.ViolationCloseTimer, current.ViolationCloseTimer)
+	}
+	fields.check("runbookURL", input.RunbookURL, current.RunbookURL)
+	fields.check("enabled", input.Enabled, current.Enabled)
+
+	if len(fields) > 0 {
+		_, err = GetClient(ctx).Alerts.UpdateInfrastructureCondition(*input)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		if !s.Status.specChanged(hash) {
+			s.Status.markDrift(ctx, fields)
+		} else {
+			s.Status.markInSync()
+		}
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
+	return false
+}
@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInfraAlertConditionLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := &InfraAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "cpu"},
+		Spec: InfraAlertConditionSpec{
+			PolicyName:   "policy",
+			Event:        "SystemSample",
+			Select:       "cpuPercent",
+			Where:        "environment = 'production'",
+			Applications: []string{"web", "api"},
+			Critical:     InfraAlertThreshold{Value: 90, DurationMinutes: 5},
+		},
+	}
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+
+	data, err := account.GetInfrastructureCondition(*condition.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.PolicyID != policy.ID || data.Type != InfraMetric || data.Comparison != "above" || data.Critical.Function != "all" {
+		t.Fatalf("unexpected defaults %+v", data)
+	}
+	where := "(environment = 'production') AND (apmApplicationNames LIKE '%|web|%' OR apmApplicationNames LIKE '%|api|%')"
+	if data.Where != where {
+		t.Fatalf("unexpected where clause %s", data.Where)
+	}
+
+	condition.Spec.Critical.Value = 95
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	data, err = account.GetInfrastructureCondition(data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Critical.Value != 95 {
+		t.Fatalf("expected condition to be updated, got %+v", data.Critical)
+	}
+
+	if condition.Delete(ctx) {
+		t.Fatalf("delete failed: %s", condition.Status.Info)
+	}
+	if _, err = account.GetInfrastructureCondition(data.ID); err == nil {
+		t.Fatal("expected condition to be deleted")
+	}
+}
+
+func TestInfraAlertConditionMovePolicy(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	if _, err := account.CreatePolicy(alerts.Policy{Name: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	second, err := account.CreatePolicy(alerts.Policy{Name: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := &InfraAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "host"},
+		Spec: InfraAlertConditionSpec{
+			PolicyName: "first",
+			Type:       InfraHostNotReporting,
+			Critical:   InfraAlertThreshold{DurationMinutes: 5},
+		},
+	}
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+	id := *condition.Status.GetID()
+
+	condition.Spec.PolicyName = "second"
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	if _, err = account.GetInfrastructureCondition(id); err == nil {
+		t.Fatal("expected the condition on the first policy to be deleted")
+	}
+	data, err := account.GetInfrastructureCondition(*condition.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.PolicyID != second.ID || data.Comparison != "" {
+		t.Fatalf("unexpected condition %+v", data)
+	}
+}
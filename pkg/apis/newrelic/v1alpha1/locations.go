@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+)
+
+// resolveLocations checks the locations against the locations of the account, which are cached by the client,
+// private locations can be given by their name or their label and are replaced by their name
+func resolveLocations(ctx context.Context, names []string) ([]string, error) {
+	locations, err := GetClient(ctx).Locations()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := []string{}
+	for _, name := range names {
+		location, err := findLocation(locations, name)
+		if err != nil {
+			return nil, invalidSpec(err)
+		}
+		resolved = append(resolved, location.Name)
+	}
+	return resolved, nil
+}
+
+func findLocation(locations []*newrelic.Location, name string) (*newrelic.Location, error) {
+	matches := []*newrelic.Location{}
+	for _, location := range locations {
+		if location.Name == name {
+			return location, nil
+		}
+		if location.Private && location.Label == name {
+			matches = append(matches, location)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown location %s, it is neither a public location nor a private location of the account", name)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("location %s is the label of %d private locations, use the name of the location instead", name, len(matches))
+}
@@ -1,11 +1,13 @@
 package v1alpha1
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
@@ -14,16 +16,39 @@ import (
 
 // MonitorSpec defines the desired state of Monitor
 type MonitorSpec struct {
-	Type          *string              `json:"type,omitempty"`
-	Frequency     *int64               `json:"frequency,omitempty"`
-	URI           *string              `json:"uri,omitempty"`
-	Locations     []*string            `json:"locations,omitempty"`
-	Status        *MonitorStatusString `json:"status,omitempty"`
-	SLAThreshold  *float64             `json:"slaThreshold,omitempty"`
-	ManageUpdates *bool                `json:"manageUpdates,omitempty"`
-	Options       MonitorOptions       `json:"options,omitempty"`
-	Script        *Script              `json:"script,omitempty"`
-	Conditions    []Conditions         `json:"conditions,omitempty"`
+	// Name of the New Relic monitor, defaults to the name of the resource
+	Name string `json:"name,omitempty"`
+	// Type defaults to SIMPLE
+	// +kubebuilder:validation:Enum=SIMPLE;BROWSER;SCRIPT_BROWSER;SCRIPT_API
+	// +kubebuilder:default=SIMPLE
+	Type *string `json:"type,omitempty"`
+	// Frequency in minutes, defaults to 10
+	// +kubebuilder:validation:Enum=1;5;10;15;30;60;360;720;1440
+	// +kubebuilder:default=10
+	Frequency *int64  `json:"frequency,omitempty"`
+	URI       *string `json:"uri,omitempty"`
+	// Locations the monitor runs from, defaults to AWS_US_WEST_1, private locations can be given by their name or label
+	// +kubebuilder:default={AWS_US_WEST_1}
+	Locations []*string `json:"locations,omitempty"`
+	// Status defaults to enabled
+	// +kubebuilder:validation:Enum=enabled;disabled;muted
+	// +kubebuilder:default=enabled
+	Status *MonitorStatusString `json:"status,omitempty"`
+	// SLAThreshold in seconds, defaults to 1.0
+	// +kubebuilder:validation:Type=number
+	// +kubebuilder:default=1
+	SLAThreshold  *float64                     `json:"slaThreshold,omitempty"`
+	ManageUpdates *bool                        `json:"manageUpdates,omitempty"`
+	Options       MonitorOptions               `json:"options,omitempty"`
+	Script        *Script                      `json:"script,omitempty"`
+	Conditions    []Conditions                 `json:"conditions,omitempty"`
+	AccountRef    *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -31,11 +56,31 @@ type MonitorSpec struct {
 // Monitor is the Schema for the monitors API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=monitors,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Monitor struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
-	Spec              MonitorSpec `json:"spec"`
-	Status            Status      `json:"status,omitempty"`
+	Spec              MonitorSpec   `json:"spec"`
+	Status            MonitorStatus `json:"status,omitempty"`
+}
+
+// MonitorStatus is the shared status with what a Monitor keeps track of in New Relic
+type MonitorStatus struct {
+	Status `json:",inline"`
+	// SyntheticsConditions are the alert conditions the monitor created in its policies
+	SyntheticsConditions []SyntheticsConditionStatus `json:"syntheticsConditions,omitempty"`
+	// ScriptHash is the digest of the script last uploaded to a scripted monitor
+	ScriptHash []byte `json:"scriptHash,omitempty"`
+}
+
+// SyntheticsConditionStatus is an alert condition created for a Monitor
+type SyntheticsConditionStatus struct {
+	PolicyName string `json:"policyName"`
+	PolicyID   int    `json:"policyID"`
+	ID         int    `json:"id"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -84,14 +129,74 @@ type MonitorOptions struct {
 	TreatRedirectAsFailure bool    `json:"treatRedirectAsFailure,omitempty"`
 }
 
+// Conditions alert on the monitor failing in a policy
 type Conditions struct {
-	PolicyName string  `json:"policyName,omitempty"`
+	PolicyName string `json:"policyName,omitempty"`
+	// PolicyRef is the AlertPolicy resource to alert in, it takes precedence over policyName
+	PolicyRef *ObjectReference `json:"policyRef,omitempty"`
+	// Name of the condition, defaults to Check Failure
+	Name       *string `json:"name,omitempty"`
 	RunbookURL *string `json:"runbookURL,omitempty"`
+	// Enabled defaults to true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// defaultConditionName is used for conditions without a name
+const defaultConditionName = "Check Failure"
+
+// policy names the policy of the condition for messages and status
+func (c Conditions) policy() string {
+	if c.PolicyRef != nil {
+		return c.PolicyRef.Name
+	}
+	return c.PolicyName
+}
+
+func (c Conditions) toNewRelic(monitorID string) alerts.SyntheticsCondition {
+	data := alerts.SyntheticsCondition{
+		Name:      defaultConditionName,
+		MonitorID: monitorID,
+		Enabled:   c.Enabled == nil || *c.Enabled,
+	}
+
+	if c.Name != nil {
+		data.Name = *c.Name
+	}
+	if c.RunbookURL != nil {
+		data.RunbookURL = *c.RunbookURL
+	}
+	return data
 }
 
-// TODO flatten this structure out
+// Script of SCRIPT_API and SCRIPT_BROWSER monitors, set inline or read from a ConfigMap or Secret
 type Script struct {
 	ScriptText *string `json:"scriptText,omitempty"`
+	// ScriptFrom reads the script from a ConfigMap or Secret key, it can not be combined with scriptText
+	ScriptFrom *ScriptSource `json:"scriptFrom,omitempty"`
+}
+
+// ScriptSource selects the key holding the script, only one may be set
+type ScriptSource struct {
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *corev1.SecretKeySelector    `json:"secretKeyRef,omitempty"`
+}
+
+// validate checks that the script is set inline or read from exactly one source
+func (s *Script) validate() error {
+	switch {
+	case s.ScriptFrom == nil:
+		return nil
+	case s.ScriptText != nil:
+		return errors.New("script.scriptText and script.scriptFrom can not be used together")
+	case (s.ScriptFrom.ConfigMapKeyRef != nil) == (s.ScriptFrom.SecretKeyRef != nil):
+		return errors.New("script.scriptFrom requires either configMapKeyRef or secretKeyRef")
+	}
+	return nil
+}
+
+// isSet reports if the script is set inline or read from a ConfigMap or Secret
+func (s *Script) isSet() bool {
+	return s != nil && (s.ScriptFrom != nil || (s.ScriptText != nil && *s.ScriptText != ""))
 }
 
 // IsCreated specifies if the object has been created in new relic yet
@@ -99,14 +204,158 @@ func (s *Monitor) IsCreated() bool {
 	return s.Status.IsCreated()
 }
 
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *Monitor) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// newRelicName returns the name of the New Relic monitor
+func (s *Monitor) newRelicName() string {
+	if s.Spec.Name != "" {
+		return s.Spec.Name
+	}
+	return s.GetName()
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *Monitor) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *Monitor) GetStatus() *Status {
+	return &s.Status.Status
+}
+
+// monitorFrequencies are the check intervals in minutes accepted by New Relic
+var monitorFrequencies = []int64{1, 5, 10, 15, 30, 60, 360, 720, 1440}
+
+// validate checks the spec without calling New Relic
+func (s *Monitor) validate() error {
+	monitorType := string(typePing)
+	if s.Spec.Type != nil {
+		monitorType = *s.Spec.Type
+	}
+	switch monitorType {
+	case string(typePing), typeBrowser:
+	case typeScriptedBrowser, typeAPI:
+		if !s.Spec.Script.isSet() {
+			return fmt.Errorf("script.scriptText or script.scriptFrom is required for %s monitors", monitorType)
+		}
+	default:
+		return fmt.Errorf("unknown type %s, expected one of SIMPLE, BROWSER, SCRIPT_BROWSER or SCRIPT_API", monitorType)
+	}
+
+	if s.Spec.Script != nil {
+		if err := s.Spec.Script.validate(); err != nil {
+			return err
+		}
+	}
+
+	if s.Spec.Frequency != nil && !containsInt64(monitorFrequencies, *s.Spec.Frequency) {
+		return fmt.Errorf("frequency %d is not one of %v", *s.Spec.Frequency, monitorFrequencies)
+	}
+
+	// locations are only known to the account, resolveLocations checks them while syncing
+	for _, location := range s.Spec.Locations {
+		if location == nil || *location == "" {
+			return errors.New("locations can not be empty")
+		}
+	}
+
+	if s.Spec.Status != nil {
+		switch *s.Spec.Status {
+		case Enabled, Disabled, Muted:
+		default:
+			return fmt.Errorf("unknown status %s, expected one of enabled, disabled or muted", *s.Spec.Status)
+		}
+	}
+
+	if s.Spec.SLAThreshold != nil && *s.Spec.SLAThreshold <= 0 {
+		return errors.New("slaThreshold must be greater than 0")
+	}
+
+	for _, item := range s.Spec.Conditions {
+		if err := validatePolicy(item.PolicyName, item.PolicyRef); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Defaults of monitors, the CRD applies them through its OpenAPI schema and the mutating webhook through Default
+const (
+	defaultMonitorFrequency    int64   = 10
+	defaultMonitorLocation             = "AWS_US_WEST_1"
+	defaultMonitorSLAThreshold float64 = 1.0
+)
+
+// Default sets the type, frequency, locations, SLA threshold and status left out of the spec
+func (s *Monitor) Default() {
+	s.Spec.setDefaults()
+}
+
+func (s *MonitorSpec) setDefaults() {
+	if s.Type == nil {
+		monitorType := string(typePing)
+		s.Type = &monitorType
+	}
+	if s.Frequency == nil {
+		frequency := defaultMonitorFrequency
+		s.Frequency = &frequency
+	}
+	if s.Locations == nil {
+		location := defaultMonitorLocation
+		s.Locations = []*string{&location}
+	}
+	if s.SLAThreshold == nil {
+		threshold := defaultMonitorSLAThreshold
+		s.SLAThreshold = &threshold
+	}
+	if s.Status == nil {
+		status := Enabled
+		s.Status = &status
+	}
+}
+
 func (s *Monitor) toNewRelic() (*synthetics.Monitor, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	// the apiserver fills in the defaults, they are only missing when the CRD was installed without them
+	spec := &s.Spec
+	missing := []string{}
+	if spec.Type == nil {
+		missing = append(missing, "type")
+	}
+	if spec.Frequency == nil {
+		missing = append(missing, "frequency")
+	}
+	if spec.Locations == nil {
+		missing = append(missing, "locations")
+	}
+	if spec.SLAThreshold == nil {
+		missing = append(missing, "slaThreshold")
+	}
+	if spec.Status == nil {
+		missing = append(missing, "status")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s not set, apply the current CRD to default them", strings.Join(missing, ", "))
+	}
 
 	data := &synthetics.Monitor{
-		Name: s.ObjectMeta.Name,
+		Name:         s.newRelicName(),
+		Type:         synthetics.MonitorType(*spec.Type),
+		Frequency:    uint(*spec.Frequency),
+		Locations:    []string{},
+		SLAThreshold: *spec.SLAThreshold,
+		Status:       synthetics.MonitorStatusType(spec.Status.String()),
 		Options: synthetics.MonitorOptions{
-			VerifySSL:              s.Spec.Options.VerifySSL,
-			BypassHEADRequest:      s.Spec.Options.BypassHEADRequest,
-			TreatRedirectAsFailure: s.Spec.Options.TreatRedirectAsFailure,
+			VerifySSL:              spec.Options.VerifySSL,
+			BypassHEADRequest:      spec.Options.BypassHEADRequest,
+			TreatRedirectAsFailure: spec.Options.TreatRedirectAsFailure,
 		},
 	}
 
@@ -114,78 +363,102 @@ func (s *Monitor) toNewRelic() (*synthetics.Monitor, error) {
 		data.ID = *s.Status.ID
 	}
 
-	if s.Spec.Status != nil {
-		data.Status = synthetics.MonitorStatusType(s.Spec.Status.String())
+	for _, item := range spec.Locations {
+		data.Locations = append(data.Locations, *item)
 	}
 
-	if s.Spec.Type == nil {
-		data.Type = synthetics.MonitorType(typePing)
-	} else {
-		data.Type = synthetics.MonitorType(*s.Spec.Type)
+	if spec.Options.ValidationString != nil {
+		data.Options.ValidationString = *spec.Options.ValidationString
 	}
 
-	if s.Spec.Frequency == nil {
-		data.Frequency = 10
-	} else {
-		data.Frequency = uint(*s.Spec.Frequency)
+	if spec.URI != nil {
+		data.URI = *spec.URI
 	}
 
-	if s.Spec.Locations == nil {
-		data.Locations = []string{"AWS_US_WEST_1"}
-	} else {
-		data.Locations = []string{}
-		for _, item := range s.Spec.Locations {
-			data.Locations = append(data.Locations, *item)
+	return data, nil
+}
+
+// Adopt takes over an existing monitor matching importID, or matching the name when adoptExisting is set
+func (s *Monitor) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID != nil {
+		data, err := GetClient(ctx).Synthetics.GetMonitor(*s.Spec.ImportID)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
 		}
-	}
 
-	if s.Spec.SLAThreshold != nil {
-		data.SLAThreshold = *s.Spec.SLAThreshold
-	} else {
-		data.SLAThreshold = 1.0
+		s.Status.adopted(ctx, data.ID)
+		return false
 	}
 
-	if s.Spec.Options.ValidationString != nil {
-		data.Options.ValidationString = *s.Spec.Options.ValidationString
+	if !s.Spec.AdoptExisting {
+		return false
 	}
 
-	if s.Spec.URI != nil {
-		data.URI = *s.Spec.URI
+	items, err := GetClient(ctx).Synthetics.ListMonitors()
+	if s.Status.HandleOnError(ctx, err) {
+		return true
 	}
 
-	if s.Spec.Status == nil {
-		data.Status = synthetics.MonitorStatusType(Enabled.String())
+	ids := []string{}
+	for _, item := range items {
+		if item.Name == s.newRelicName() {
+			ids = append(ids, item.ID)
+		}
 	}
 
-	return data, nil
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.newRelicName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, ids[0])
+	}
+	return false
 }
 
 // Create in newrelic
 func (s *Monitor) Create(ctx context.Context) bool {
 	input, err := s.toNewRelic()
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	input.Locations, err = resolveLocations(ctx, input.Locations)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	data, err := client.Synthetics.CreateMonitor(*input)
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	script, err := s.scriptText(ctx)
+	if s.Status.HandleOnErrorMessage(ctx, err, "failed on script") {
+		return true
+	}
+
+	data, err := GetClient(ctx).Synthetics.CreateMonitor(*input)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
 	s.Status.Info = "Created"
 	s.Status.ID = &data.ID
-	s.SetFinalizers([]string{finalizer})
 
-	err = s.updateScript(ctx)
-	if s.Status.HandleOnError(ctx, err) {
+	err = s.uploadScript(ctx, script)
+	if s.Status.HandleOnErrorMessage(ctx, err, "failed on script") {
 		return true
 	}
 
-	err = s.updateCondition(ctx)
+	_, err = s.updateConditions(ctx)
 	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
+	s.Status.Hash = hash
 	return false
 }
 
@@ -198,8 +471,13 @@ func (s *Monitor) Delete(ctx context.Context) bool {
 		return false
 	}
 
-	err := client.Synthetics.DeleteMonitor(*s.Status.ID)
-	if err != nil {
+	err := s.deleteConditions(ctx, nil)
+	if s.Status.HandleOnErrorMessage(ctx, err, "failed on condition") {
+		return true
+	}
+
+	err = GetClient(ctx).Synthetics.DeleteMonitor(*s.Status.ID)
+	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
@@ -209,129 +487,273 @@ func (s *Monitor) Delete(ctx context.Context) bool {
 // Update object in newrelic
 func (s *Monitor) Update(ctx context.Context) bool {
 	monitor, err := s.toNewRelic()
-	if s.Status.HandleOnError(ctx, err) {
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
 		return true
 	}
 
-	if s.Spec.ManageUpdates != nil && *s.Spec.ManageUpdates {
-		data, err := s.getCurrent(ctx)
-		if err != nil {
-			return true
-		}
-		monitor.Status = data.Status
+	monitor.Locations, err = resolveLocations(ctx, monitor.Locations)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
 	}
 
-	s.Status.Info = "Updated"
-	_, err = client.Synthetics.UpdateMonitor(*monitor)
-	if s.Status.HandleOnErrorMessage(ctx, err, "failed") {
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
 		return true
 	}
 
-	err = s.updateScript(ctx)
+	script, err := s.scriptText(ctx)
 	if s.Status.HandleOnErrorMessage(ctx, err, "failed on script") {
 		return true
 	}
 
-	err = s.updateCondition(ctx)
+	current, err := s.getCurrent(ctx)
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnErrorMessage(ctx, err, "failed to read monitor") {
+		return true
+	}
+
+	if s.Spec.ManageUpdates != nil && *s.Spec.ManageUpdates {
+		monitor.Status = current.Status
+	}
+
+	fields := monitorDrift(monitor, current)
+	if len(fields) > 0 {
+		s.Status.Info = "Updated"
+		_, err = GetClient(ctx).Synthetics.UpdateMonitor(*monitor)
+		if s.Status.HandleOnErrorMessage(ctx, err, "failed") {
+			return true
+		}
+	}
+
+	if s.scriptChanged(script) {
+		err = s.uploadScript(ctx, script)
+		if s.Status.HandleOnErrorMessage(ctx, err, "failed on script") {
+			return true
+		}
+	}
+
+	changed, err := s.updateConditions(ctx)
 	if s.Status.HandleOnErrorMessage(ctx, err, "failed on condition") {
 		return true
 	}
+	if changed {
+		fields = append(fields, "conditions")
+	}
 
+	if len(fields) > 0 && !s.Status.specChanged(hash) {
+		s.Status.markDrift(ctx, fields)
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
 	return false
 }
 
-func (s *Monitor) updateScript(ctx context.Context) error {
-	if s.Spec.Type != nil && strings.ToUpper(*s.Spec.Type) == typeAPI && s.Spec.Script != nil && s.Spec.Script.ScriptText != nil {
-		_, err := client.Synthetics.UpdateMonitorScript(*s.Status.ID, synthetics.MonitorScript{
-			Text: *s.Spec.Script.ScriptText,
-		})
+// monitorDrift lists the fields of the live monitor that differ from the desired monitor
+func monitorDrift(desired *synthetics.Monitor, live *synthetics.Monitor) drift {
+	fields := drift{}
+	fields.check("name", desired.Name, live.Name)
+	fields.check("type", desired.Type, live.Type)
+	fields.check("frequency", desired.Frequency, live.Frequency)
+	fields.check("uri", desired.URI, live.URI)
+	fields.check("locations", sortedStrings(desired.Locations), sortedStrings(live.Locations))
+	fields.check("status", strings.ToUpper(string(desired.Status)), strings.ToUpper(string(live.Status)))
+	fields.check("slaThreshold", desired.SLAThreshold, live.SLAThreshold)
+	fields.check("options", desired.Options, live.Options)
+	return fields
+}
+
+// isScripted reports if the monitor runs a script
+func (s *Monitor) isScripted() bool {
+	return s.Spec.Type != nil && (*s.Spec.Type == typeAPI || *s.Spec.Type == typeScriptedBrowser)
+}
+
+// scriptText returns the script of scripted monitors from the spec or the referenced ConfigMap or Secret
+func (s *Monitor) scriptText(ctx context.Context) (string, error) {
+	if !s.isScripted() {
+		return "", nil
+	}
+
+	var text string
+	var err error
+	source := s.Spec.Script.ScriptFrom
+	switch {
+	case source == nil:
+		text = *s.Spec.Script.ScriptText
+	case source.ConfigMapKeyRef != nil:
+		text, err = readConfigMapKey(ctx, s.GetNamespace(), source.ConfigMapKeyRef)
+	default:
+		text, err = readSecretKey(ctx, s.GetNamespace(), source.SecretKeyRef)
+	}
+
+	if err == nil && text == "" {
+		err = invalidSpec(errors.New("the script is empty"))
+	}
+	return text, err
+}
+
+// scriptChanged reports if the script differs from the one uploaded last
+func (s *Monitor) scriptChanged(script string) bool {
+	if !s.isScripted() {
+		return false
+	}
+	hash, err := hashSpec(script)
+	return err != nil || !bytes.Equal(hash, s.Status.ScriptHash)
+}
+
+// uploadScript replaces the script of scripted monitors and remembers its digest
+func (s *Monitor) uploadScript(ctx context.Context, script string) error {
+	if !s.isScripted() {
+		return nil
+	}
+
+	hash, err := hashSpec(script)
+	if err != nil {
 		return err
 	}
+
+	_, err = GetClient(ctx).Synthetics.UpdateMonitorScript(*s.Status.ID, synthetics.MonitorScript{
+		Text: script,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Status.ScriptHash = hash
 	return nil
 }
 
-func (s *Monitor) updateCondition(ctx context.Context) error {
-	// TODO remove what exists, but is no longer in the CR
-	if s.Spec.Conditions != nil {
-		for _, item := range s.Spec.Conditions {
-			policyID, err := s.findPolicyID(ctx, item.PolicyName)
-			if err != nil {
-				return err
-			}
+// UsesConfigMap reports if the script is read from the ConfigMap
+func (s *Monitor) UsesConfigMap(name string) bool {
+	return s.Spec.Script != nil && s.Spec.Script.ScriptFrom != nil &&
+		s.Spec.Script.ScriptFrom.ConfigMapKeyRef != nil && s.Spec.Script.ScriptFrom.ConfigMapKeyRef.Name == name
+}
 
-			result, err := client.Alerts.ListSyntheticsConditions(*policyID)
-			if err != nil {
-				return err
-			}
+// UsesSecret reports if the script is read from the Secret
+func (s *Monitor) UsesSecret(name string) bool {
+	return s.Spec.Script != nil && s.Spec.Script.ScriptFrom != nil &&
+		s.Spec.Script.ScriptFrom.SecretKeyRef != nil && s.Spec.Script.ScriptFrom.SecretKeyRef.Name == name
+}
 
-			exists := false
-			if result != nil {
-				for _, item := range result {
-					id := s.Status.GetID()
-					if id != nil && item.MonitorID == string(*id) {
-						exists = true
-					}
-				}
-			}
+// UsesAlertPolicy reports if a condition of the monitor refers to the AlertPolicy resource namespace/name
+func (s *Monitor) UsesAlertPolicy(namespace string, name string) bool {
+	for _, item := range s.Spec.Conditions {
+		if item.PolicyRef != nil && item.PolicyRef.refersTo(s.GetNamespace(), namespace, name) {
+			return true
+		}
+	}
+	return false
+}
 
-			if exists {
-				continue
-			}
+// updateConditions creates, updates and deletes the synthetics conditions of the monitor so there is one
+// in each policy of the spec, it reports if any condition had to be changed
+func (s *Monitor) updateConditions(ctx context.Context) (bool, error) {
+	changed := false
+	desired := map[int]bool{}
+	var result []SyntheticsConditionStatus
 
-			data := alerts.SyntheticsCondition{
-				Name:      "Check Failure",
-				MonitorID: *s.Status.ID,
-				Enabled:   true,
-			}
+	for _, item := range s.Spec.Conditions {
+		policyID, err := resolvePolicyID(ctx, s, item.PolicyName, item.PolicyRef)
+		if err != nil {
+			return false, err
+		}
+		if desired[policyID] {
+			return false, invalidSpec(fmt.Errorf("policy %s is used by more than one condition", item.policy()))
+		}
+		desired[policyID] = true
 
-			if item.RunbookURL != nil {
-				data.RunbookURL = *item.RunbookURL
-			}
+		input := item.toNewRelic(*s.Status.ID)
+		current, err := s.findCondition(ctx, policyID)
+		if err != nil {
+			return false, err
+		}
 
-			_, err = client.Alerts.CreateSyntheticsCondition(*policyID, data)
+		if current == nil {
+			current, err = GetClient(ctx).Alerts.CreateSyntheticsCondition(policyID, input)
 			if err != nil {
-				return err
+				return false, err
+			}
+			changed = true
+		} else {
+			input.ID = current.ID
+			if input != *current {
+				current, err = GetClient(ctx).Alerts.UpdateSyntheticsCondition(input)
+				if err != nil {
+					return false, err
+				}
+				changed = true
 			}
 		}
+
+		result = append(result, SyntheticsConditionStatus{PolicyName: item.policy(), PolicyID: policyID, ID: current.ID})
 	}
-	return nil
-}
 
-func (s *Monitor) getCurrent(ctx context.Context) (*synthetics.Monitor, error) {
-	id := s.Status.GetID()
-	if id == nil {
-		return nil, errors.New("missing id")
+	err := s.deleteConditions(ctx, desired)
+	if err != nil {
+		return false, err
 	}
 
-	return client.Synthetics.GetMonitor(string(*id))
+	s.Status.SyntheticsConditions = result
+	return changed, nil
 }
 
-func (s *Monitor) findPolicyID(ctx context.Context, name string) (*int, error) {
-	logger := GetLogger(ctx)
-	policies, err := client.Alerts.ListPolicies(&alerts.ListPoliciesParams{Name: name})
+// findCondition returns the condition of the monitor in the policy, preferring the one recorded in status
+func (s *Monitor) findCondition(ctx context.Context, policyID int) (*alerts.SyntheticsCondition, error) {
+	items, err := GetClient(ctx).Alerts.ListSyntheticsConditions(policyID)
 	if err != nil {
 		return nil, err
 	}
 
-	var id *int
-	for _, item := range policies {
-		if item.Name == name {
-			if id != nil {
-				for _, item := range policies {
-					if item.Name == name {
-						logger.V(1).Info(fmt.Sprintf("duplicate policies %s %d", item.Name, item.ID))
-					}
-				}
-				err = fmt.Errorf("expected a policy search by name to only return 1 result, but found multiple for %s", name)
-				return nil, err
+	for _, recorded := range s.Status.SyntheticsConditions {
+		if recorded.PolicyID != policyID {
+			continue
+		}
+		for _, item := range items {
+			if item.ID == recorded.ID {
+				return item, nil
 			}
-			id = &item.ID
 		}
 	}
 
-	if id != nil {
-		return id, nil
+	// conditions created before their IDs were recorded, or by hand
+	for _, item := range items {
+		if item.MonitorID == *s.Status.ID {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+// deleteConditions removes the recorded conditions whose policy is not kept, nil removes all of them
+func (s *Monitor) deleteConditions(ctx context.Context, keep map[int]bool) error {
+	recorded := s.Status.SyntheticsConditions
+	remaining := []SyntheticsConditionStatus{}
+	for i, item := range recorded {
+		if keep[item.PolicyID] {
+			remaining = append(remaining, item)
+			continue
+		}
+
+		GetLogger(ctx).Info("deleting condition", "policy", item.PolicyName, "id", item.ID)
+		_, err := GetClient(ctx).Alerts.DeleteSyntheticsCondition(item.ID)
+		if err != nil && !isNotFound(err) {
+			s.Status.SyntheticsConditions = append(remaining, recorded[i:]...)
+			return err
+		}
+	}
+
+	s.Status.SyntheticsConditions = remaining
+	return nil
+}
+
+func (s *Monitor) getCurrent(ctx context.Context) (*synthetics.Monitor, error) {
+	if s.Status.ID == nil {
+		return nil, errors.New("missing id")
 	}
 
-	return nil, fmt.Errorf("unable to find policy %s", name)
+	return GetClient(ctx).Synthetics.GetMonitor(*s.Status.ID)
 }
@@ -0,0 +1,391 @@
+package v1alpha1
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMonitorLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri := "https://example.com"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec: MonitorSpec{
+			URI:        &uri,
+			Conditions: []Conditions{{PolicyName: "policy"}},
+		},
+	}
+	monitor.Default()
+
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+	if !monitor.IsCreated() {
+		t.Fatal("expected monitor to have an id")
+	}
+
+	data, err := account.GetMonitor(*monitor.Status.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.URI != uri || data.Frequency != 10 || data.Locations[0] != "AWS_US_WEST_1" {
+		t.Fatalf("unexpected monitor %+v", data)
+	}
+
+	conditions, err := account.ListSyntheticsConditions(policy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conditions) != 1 || conditions[0].MonitorID != *monitor.Status.ID {
+		t.Fatalf("expected a single condition for the monitor, got %v", conditions)
+	}
+
+	frequency := int64(5)
+	monitor.Spec.Frequency = &frequency
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+
+	data, err = account.GetMonitor(*monitor.Status.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Frequency != 5 {
+		t.Fatalf("expected frequency to be updated, got %d", data.Frequency)
+	}
+
+	conditions, err = account.ListSyntheticsConditions(policy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conditions) != 1 {
+		t.Fatalf("expected update to not duplicate conditions, got %v", conditions)
+	}
+
+	if monitor.Delete(ctx) {
+		t.Fatalf("delete failed: %s", monitor.Status.Info)
+	}
+	if _, err = account.GetMonitor(*monitor.Status.ID); err == nil {
+		t.Fatal("expected monitor to be deleted")
+	}
+}
+
+func TestMonitorMissingPolicy(t *testing.T) {
+	ctx := WithClient(context.TODO(), fake.New())
+
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec: MonitorSpec{
+			Conditions: []Conditions{{PolicyName: "missing"}},
+		},
+	}
+	monitor.Default()
+
+	if !monitor.Create(ctx) {
+		t.Fatal("expected create to requeue when the policy does not exist")
+	}
+	if monitor.Status.Info != "unable to find policy missing" {
+		t.Fatalf("unexpected info %q", monitor.Status.Info)
+	}
+}
+
+func TestMonitorLocations(t *testing.T) {
+	account := fake.NewAccount()
+	account.AddPrivateLocation("1234-vpc-east", "vpc-east")
+	client := account.Client()
+	ctx := WithClient(context.TODO(), client)
+
+	vpc, eu := "vpc-east", "AWS_EU_WEST_1"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec:       MonitorSpec{Locations: []*string{&vpc, &eu}},
+	}
+	monitor.Default()
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+
+	data, err := client.Synthetics.GetMonitor(*monitor.Status.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data.Locations, []string{"1234-vpc-east", "AWS_EU_WEST_1"}) {
+		t.Fatalf("expected the private location to be resolved by its label, got %v", data.Locations)
+	}
+
+	// the locations are cached so a new private location is only known once the cache expires
+	account.AddPrivateLocation("5678-office", "office")
+	office := "office"
+	monitor.Spec.Locations = []*string{&office}
+	if !monitor.Update(ctx) {
+		t.Fatal("expected update to fail on the unknown location")
+	}
+	if c := monitor.Status.GetCondition(ConditionReady); c == nil || c.Reason != ReasonInvalidSpec ||
+		c.Message != "unknown location office, it is neither a public location nor a private location of the account" {
+		t.Fatalf("expected the unknown location to be reported, got %+v", c)
+	}
+
+	if monitor.Update(WithClient(context.TODO(), account.Client())) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+}
+
+func TestMonitorDeletedRemote(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+	uri := "https://example.com"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec:       MonitorSpec{URI: &uri},
+	}
+	monitor.Default()
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, monitor)
+
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+	id := *monitor.Status.ID
+	if err := account.DeleteMonitor(id); err != nil {
+		t.Fatal(err)
+	}
+
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+	if !monitor.IsCreated() || *monitor.Status.ID == id {
+		t.Fatalf("expected monitor to be recreated, got %v", monitor.Status.ID)
+	}
+	if _, err := account.GetMonitor(*monitor.Status.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(recorder.Events))
+	}
+}
+
+func TestMonitorConditions(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+
+	first, err := account.CreatePolicy(alerts.Policy{Name: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := account.CreatePolicy(alerts.Policy{Name: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri := "https://example.com"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec: MonitorSpec{
+			URI:        &uri,
+			Conditions: []Conditions{{PolicyName: "first"}, {PolicyName: "second"}},
+		},
+	}
+	monitor.Default()
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, monitor)
+
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+	if len(monitor.Status.SyntheticsConditions) != 2 || monitor.Status.SyntheticsConditions[1].PolicyID != second.ID {
+		t.Fatalf("expected both conditions in status, got %+v", monitor.Status.SyntheticsConditions)
+	}
+
+	name := "Website Down"
+	runbook := "https://example.com/runbook"
+	monitor.Spec.Conditions = []Conditions{{PolicyName: "first", Name: &name, RunbookURL: &runbook}}
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+
+	conditions, err := account.ListSyntheticsConditions(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conditions) != 1 || conditions[0].Name != name || conditions[0].RunbookURL != runbook || !conditions[0].Enabled {
+		t.Fatalf("expected the condition to be updated, got %+v", conditions)
+	}
+	if conditions, _ = account.ListSyntheticsConditions(second.ID); len(conditions) != 0 {
+		t.Fatalf("expected the condition of the removed policy to be deleted, got %+v", conditions)
+	}
+	if len(monitor.Status.SyntheticsConditions) != 1 || monitor.Status.SyntheticsConditions[0].PolicyID != first.ID {
+		t.Fatalf("expected a single condition in status, got %+v", monitor.Status.SyntheticsConditions)
+	}
+
+	// a condition deleted by hand is recreated and reported as drift
+	if _, err = account.DeleteSyntheticsCondition(monitor.Status.SyntheticsConditions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+	if conditions, _ = account.ListSyntheticsConditions(first.ID); len(conditions) != 1 {
+		t.Fatalf("expected the condition to be recreated, got %+v", conditions)
+	}
+	if !monitor.Status.IsConditionTrue(ConditionDrifted) || len(recorder.Events) != 1 {
+		t.Fatalf("expected drift to be recorded, got %+v", monitor.Status.Conditions)
+	}
+
+	if monitor.Delete(ctx) {
+		t.Fatalf("delete failed: %s", monitor.Status.Info)
+	}
+	if conditions, _ = account.ListSyntheticsConditions(first.ID); len(conditions) != 0 {
+		t.Fatalf("expected conditions to be deleted with the monitor, got %+v", conditions)
+	}
+}
+
+func TestMonitorDefault(t *testing.T) {
+	frequency := int64(5)
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		Spec:       MonitorSpec{Frequency: &frequency},
+	}
+
+	// defaults come from the CRD or the webhook, a monitor stored without them is not synced with made up values
+	if _, err := monitor.toNewRelic(); err == nil || !strings.Contains(err.Error(), "type, locations, slaThreshold, status") {
+		t.Fatalf("expected the missing defaults to be reported, got %v", err)
+	}
+
+	monitor.Default()
+	if *monitor.Spec.Type != string(typePing) || *monitor.Spec.Frequency != 5 || *monitor.Spec.Locations[0] != defaultMonitorLocation ||
+		*monitor.Spec.SLAThreshold != defaultMonitorSLAThreshold || *monitor.Spec.Status != Enabled {
+		t.Fatalf("unexpected defaults %+v", monitor.Spec)
+	}
+
+	data, err := monitor.toNewRelic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Type != synthetics.MonitorType(typePing) || data.Frequency != 5 || data.SLAThreshold != defaultMonitorSLAThreshold {
+		t.Fatalf("unexpected monitor %+v", data)
+	}
+}
+
+func TestMonitorScriptFromConfigMap(t *testing.T) {
+	account := fake.NewAccount()
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	monitorType := typeScriptedBrowser
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "login", Namespace: "team"},
+		Spec: MonitorSpec{
+			Type: &monitorType,
+			Script: &Script{ScriptFrom: &ScriptSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scripts"}, Key: "login.js"},
+			}},
+		},
+	}
+	monitor.Default()
+	if !monitor.UsesConfigMap("scripts") || monitor.UsesSecret("scripts") {
+		t.Fatal("expected the monitor to use the scripts ConfigMap only")
+	}
+
+	if !monitor.Create(ctx) {
+		t.Fatal("expected create to wait for the ConfigMap")
+	}
+	if monitor.IsCreated() {
+		t.Fatal("expected the monitor to not be created without its script")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "scripts", Namespace: "team"},
+		Data:       map[string]string{"login.js": "$browser.get('https://example.com/login')"},
+	}
+	if err := kube.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+
+	script := func() string {
+		data, err := account.GetMonitorScript(*monitor.Status.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data.Text
+	}
+	if script() != configMap.Data["login.js"] {
+		t.Fatalf("expected the script to be uploaded, got %q", script())
+	}
+
+	// an unchanged script is not uploaded again
+	if _, err := account.UpdateMonitorScript(*monitor.Status.ID, synthetics.MonitorScript{Text: "edited"}); err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+	if script() != "edited" {
+		t.Fatal("expected an unchanged script to not be uploaded")
+	}
+
+	configMap.Data["login.js"] = "$browser.get('https://example.com/logout')"
+	if err := kube.Update(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+	if script() != configMap.Data["login.js"] {
+		t.Fatalf("expected the edited script to be uploaded, got %q", script())
+	}
+}
+
+func TestMonitorNewRelicName(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	uri := "https://example.com"
+	monitor := &Monitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       MonitorSpec{Name: "Example Home Page", URI: &uri},
+	}
+	monitor.Default()
+
+	if monitor.Create(ctx) {
+		t.Fatalf("create failed: %s", monitor.Status.Info)
+	}
+
+	data, err := account.GetMonitor(*monitor.Status.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Name != "Example Home Page" {
+		t.Fatalf("expected the monitor to be named by spec.name, got %s", data.Name)
+	}
+
+	// the name in New Relic matches the spec, so there is no drift to repair
+	if monitor.Update(ctx) {
+		t.Fatalf("update failed: %s", monitor.Status.Info)
+	}
+	if condition := monitor.Status.GetCondition(ConditionDrifted); condition != nil && condition.Status == corev1.ConditionTrue {
+		t.Fatalf("expected no drift, got %+v", condition)
+	}
+}
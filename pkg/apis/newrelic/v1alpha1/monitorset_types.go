@@ -0,0 +1,364 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MonitorSetSpec defines the desired state of MonitorSet
+type MonitorSetSpec struct {
+	// Template is rendered for each set of parameters, $(name) in its strings is replaced by the value of the parameter name
+	Template MonitorTemplate `json:"template"`
+	// Generators produce the sets of parameters, a monitor is rendered for each set of every generator
+	// +kubebuilder:validation:MinItems=1
+	Generators []MonitorSetGenerator `json:"generators"`
+}
+
+// MonitorTemplate is the Monitor rendered for each set of parameters
+type MonitorTemplate struct {
+	// Name of the monitors, defaults to the name of the set followed by the values of the parameters ordered by parameter name
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Spec   MonitorSpec       `json:"spec"`
+}
+
+// MonitorSetGenerator produces sets of parameters, exactly one of its fields has to be set
+type MonitorSetGenerator struct {
+	// List are the sets of parameters
+	List []map[string]string `json:"list,omitempty"`
+	// Services produces the parameters name, address and port for each selected Service
+	Services *ServiceGenerator `json:"services,omitempty"`
+	// Matrix combines each set of parameters of each generator with every set of the other generators
+	Matrix []MatrixGenerator `json:"matrix,omitempty"`
+}
+
+// MatrixGenerator is a generator combined in a matrix, exactly one of its fields has to be set
+type MatrixGenerator struct {
+	List     []map[string]string `json:"list,omitempty"`
+	Services *ServiceGenerator   `json:"services,omitempty"`
+}
+
+// ServiceGenerator selects Services of type LoadBalancer in the namespace of the set, Services without a load balancer address are skipped
+type ServiceGenerator struct {
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// MonitorSetStatus defines the observed state of MonitorSet
+type MonitorSetStatus struct {
+	// Phase is a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation last processed by the operator
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	Conditions         []Condition `json:"conditions,omitempty"`
+	// Monitors are the names of the generated monitors
+	Monitors []string `json:"monitors,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MonitorSet is the Schema for the monitorsets API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=monitorsets,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type MonitorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              MonitorSetSpec   `json:"spec"`
+	Status            MonitorSetStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MonitorSetList contains a list of MonitorSet
+type MonitorSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []MonitorSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MonitorSet{}, &MonitorSetList{})
+}
+
+// Additional Code
+
+// parameterReference matches $(name) in the template
+var parameterReference = regexp.MustCompile(`\$\(([A-Za-z0-9_.-]+)\)`)
+
+// invalidNameCharacters are replaced in the names of generated monitors
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// maxNameLength is the longest name of a Monitor resource
+const maxNameLength = 253
+
+// MonitorName joins parts into a valid and unique name for a generated Monitor
+func MonitorName(parts ...string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-"), "-.")
+	if len(name) <= maxNameLength {
+		return name
+	}
+
+	// keep long names unique by ending them with a digest of the full name
+	sum := sha256.Sum256([]byte(name))
+	return fmt.Sprintf("%s-%x", strings.Trim(name[:maxNameLength-9], "-."), sum[:4])
+}
+
+// UsesServices reports if a generator selects Services
+func (s *MonitorSet) UsesServices() bool {
+	for _, generator := range s.Spec.Generators {
+		if generator.Services != nil {
+			return true
+		}
+		for _, item := range generator.Matrix {
+			if item.Services != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validate checks the spec without reading Services
+func (s *MonitorSet) validate() error {
+	if len(s.Spec.Generators) == 0 {
+		return errors.New("at least one generator is required")
+	}
+
+	for i, generator := range s.Spec.Generators {
+		set := 0
+		if generator.List != nil {
+			set++
+		}
+		if generator.Services != nil {
+			set++
+		}
+		if generator.Matrix != nil {
+			set++
+		}
+		if set != 1 {
+			return fmt.Errorf("generators[%d] needs exactly one of list, services or matrix", i)
+		}
+
+		if err := validateServiceGenerator(generator.Services); err != nil {
+			return fmt.Errorf("generators[%d]: %v", i, err)
+		}
+		for j, item := range generator.Matrix {
+			if (item.List == nil) == (item.Services == nil) {
+				return fmt.Errorf("generators[%d].matrix[%d] needs exactly one of list or services", i, j)
+			}
+			if err := validateServiceGenerator(item.Services); err != nil {
+				return fmt.Errorf("generators[%d].matrix[%d]: %v", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateServiceGenerator(generator *ServiceGenerator) error {
+	if generator == nil {
+		return nil
+	}
+	_, err := metav1.LabelSelectorAsSelector(&generator.Selector)
+	return err
+}
+
+// Monitors renders the template for each set of parameters
+func (s *MonitorSet) Monitors(ctx context.Context) ([]Monitor, error) {
+	if err := s.validate(); err != nil {
+		return nil, invalidSpec(err)
+	}
+
+	sets, err := s.parameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(sets)
+}
+
+// parameters returns the sets of parameters of all generators
+func (s *MonitorSet) parameters(ctx context.Context) ([]map[string]string, error) {
+	sets := []map[string]string{}
+	for _, generator := range s.Spec.Generators {
+		if generator.Matrix == nil {
+			generated, err := s.generate(ctx, generator.List, generator.Services)
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, generated...)
+			continue
+		}
+
+		combined := []map[string]string{{}}
+		for _, item := range generator.Matrix {
+			generated, err := s.generate(ctx, item.List, item.Services)
+			if err != nil {
+				return nil, err
+			}
+			combined = combine(combined, generated)
+		}
+		sets = append(sets, combined...)
+	}
+	return sets, nil
+}
+
+// combine returns the union of each set of a with each set of b
+func combine(a []map[string]string, b []map[string]string) []map[string]string {
+	combined := []map[string]string{}
+	for _, left := range a {
+		for _, right := range b {
+			set := map[string]string{}
+			for key, value := range left {
+				set[key] = value
+			}
+			for key, value := range right {
+				set[key] = value
+			}
+			combined = append(combined, set)
+		}
+	}
+	return combined
+}
+
+// generate returns the list, or the parameters of the Services selected
+func (s *MonitorSet) generate(ctx context.Context, list []map[string]string, services *ServiceGenerator) ([]map[string]string, error) {
+	if services == nil {
+		return list, nil
+	}
+
+	kube := getKubeClient(ctx)
+	if kube == nil {
+		return nil, errors.New("unable to read Services without a Kubernetes client")
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&services.Selector)
+	if err != nil {
+		return nil, invalidSpec(err)
+	}
+
+	items := &corev1.ServiceList{}
+	err = kube.List(ctx, items, client.InNamespace(s.GetNamespace()), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items.Items, func(i, j int) bool { return items.Items[i].Name < items.Items[j].Name })
+
+	sets := []map[string]string{}
+	for _, service := range items.Items {
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 || len(service.Spec.Ports) == 0 {
+			continue
+		}
+
+		address := service.Status.LoadBalancer.Ingress[0].Hostname
+		if address == "" {
+			address = service.Status.LoadBalancer.Ingress[0].IP
+		}
+		sets = append(sets, map[string]string{
+			"name":    service.Name,
+			"address": address,
+			"port":    strconv.Itoa(int(service.Spec.Ports[0].Port)),
+		})
+	}
+	return sets, nil
+}
+
+// render returns a Monitor for each set of parameters
+func (s *MonitorSet) render(sets []map[string]string) ([]Monitor, error) {
+	template, err := json.Marshal(s.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	monitors := []Monitor{}
+	for _, set := range sets {
+		unknown := ""
+		rendered := parameterReference.ReplaceAllFunc(template, func(reference []byte) []byte {
+			key := string(parameterReference.FindSubmatch(reference)[1])
+			value, ok := set[key]
+			if !ok {
+				unknown = key
+				return reference
+			}
+
+			// the value is escaped as it ends up inside a JSON string
+			escaped, _ := json.Marshal(value)
+			return escaped[1 : len(escaped)-1]
+		})
+		if unknown != "" {
+			return nil, invalidSpec(fmt.Errorf("the template refers to the parameter %s, which is missing from %v", unknown, set))
+		}
+
+		item := MonitorTemplate{}
+		if err := json.Unmarshal(rendered, &item); err != nil {
+			return nil, invalidSpec(err)
+		}
+
+		name := MonitorName(item.Name)
+		if item.Name == "" {
+			name = MonitorName(append([]string{s.GetName()}, parameterValues(set)...)...)
+		}
+		if names[name] {
+			return nil, invalidSpec(fmt.Errorf("more than one set of parameters renders the monitor %s, template.name has to tell them apart", name))
+		}
+		names[name] = true
+
+		monitors = append(monitors, Monitor{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.GetNamespace(), Labels: item.Labels},
+			Spec:       item.Spec,
+		})
+	}
+	return monitors, nil
+}
+
+// parameterValues returns the values of set ordered by parameter name
+func parameterValues(set map[string]string) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := []string{}
+	for _, key := range keys {
+		values = append(values, set[key])
+	}
+	return values
+}
+
+// MarkSynced records the monitors generated for the current generation
+func (s *MonitorSet) MarkSynced(monitors []Monitor) {
+	s.Status.ObservedGeneration = s.GetGeneration()
+	s.Status.Monitors = []string{}
+	for _, monitor := range monitors {
+		s.Status.Monitors = append(s.Status.Monitors, monitor.Name)
+	}
+
+	s.Status.Conditions = setCondition(s.Status.Conditions, s.Status.ObservedGeneration, ConditionReady, corev1.ConditionTrue, ReasonSynced, "")
+	s.Status.Phase = PhaseReady
+}
+
+// MarkFailed records why the monitors could not be generated
+func (s *MonitorSet) MarkFailed(err error) {
+	reason := ReasonAPIError
+	if IsInvalidSpec(err) {
+		reason = ReasonInvalidSpec
+	}
+
+	s.Status.ObservedGeneration = s.GetGeneration()
+	s.Status.Conditions = setCondition(s.Status.Conditions, s.Status.ObservedGeneration, ConditionReady, corev1.ConditionFalse, reason, err.Error())
+	s.Status.Phase = PhaseFailed
+}
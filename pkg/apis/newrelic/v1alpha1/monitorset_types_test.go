@@ -0,0 +1,121 @@
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newMonitorSet(generators ...MonitorSetGenerator) *MonitorSet {
+	uri := "https://$(env).example.com/$(path)"
+	return &MonitorSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "team"},
+		Spec: MonitorSetSpec{
+			Template: MonitorTemplate{
+				Labels: map[string]string{"env": "$(env)"},
+				Spec:   MonitorSpec{URI: &uri},
+			},
+			Generators: generators,
+		},
+	}
+}
+
+func TestMonitorSetMatrix(t *testing.T) {
+	set := newMonitorSet(
+		MonitorSetGenerator{Matrix: []MatrixGenerator{
+			{List: []map[string]string{{"env": "staging"}, {"env": "prod"}}},
+			{List: []map[string]string{{"path": "cart"}, {"path": "pay"}}},
+		}},
+		MonitorSetGenerator{List: []map[string]string{{"env": "dev", "path": "Status Page"}}},
+	)
+
+	monitors, err := set.Monitors(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, monitor := range monitors {
+		names = append(names, monitor.Name)
+	}
+	if strings.Join(names, ",") != "checkout-staging-cart,checkout-staging-pay,checkout-prod-cart,checkout-prod-pay,checkout-dev-status-page" {
+		t.Fatalf("unexpected monitors %v", names)
+	}
+	if *monitors[2].Spec.URI != "https://prod.example.com/cart" || monitors[2].Labels["env"] != "prod" || monitors[2].Namespace != "team" {
+		t.Fatalf("unexpected monitor %+v", monitors[2])
+	}
+	if *monitors[4].Spec.URI != "https://dev.example.com/Status Page" {
+		t.Fatalf("unexpected uri %s", *monitors[4].Spec.URI)
+	}
+
+	set.Spec.Template.Name = "$(env)"
+	if _, err := set.Monitors(context.TODO()); !IsInvalidSpec(err) || !strings.Contains(err.Error(), "more than one set of parameters renders the monitor staging") {
+		t.Fatalf("expected the duplicate name to be rejected, got %v", err)
+	}
+
+	set.Spec.Template.Name = "$(env)-$(region)"
+	if _, err := set.Monitors(context.TODO()); !IsInvalidSpec(err) || !strings.Contains(err.Error(), "parameter region") {
+		t.Fatalf("expected the unknown parameter to be rejected, got %v", err)
+	}
+}
+
+func TestMonitorSetServices(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	service := func(name string, app string, address string) *corev1.Service {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", Labels: map[string]string{"app": app}},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 8443}},
+			},
+		}
+		if address != "" {
+			service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: address}}
+		}
+		return service
+	}
+	kube := clientfake.NewFakeClientWithScheme(s,
+		service("web", "shop", "web.elb.example.com"),
+		service("pending", "shop", ""),
+		service("other", "blog", "other.elb.example.com"),
+	)
+	ctx := WithKubeClient(context.TODO(), kube)
+
+	uri := "https://$(address):$(port)/healthz"
+	set := newMonitorSet(MonitorSetGenerator{Services: &ServiceGenerator{
+		Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+	}})
+	set.Spec.Template = MonitorTemplate{Name: "$(name)-health", Spec: MonitorSpec{URI: &uri}}
+	if !set.UsesServices() {
+		t.Fatal("expected the set to use services")
+	}
+
+	monitors, err := set.Monitors(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(monitors) != 1 || monitors[0].Name != "web-health" || *monitors[0].Spec.URI != "https://web.elb.example.com:8443/healthz" {
+		t.Fatalf("expected a monitor for the service with an address, got %+v", monitors)
+	}
+}
+
+func TestMonitorName(t *testing.T) {
+	if name := MonitorName("web", "Shop.Example.com/api/v1/"); name != "web-shop.example.com-api-v1" {
+		t.Fatalf("unexpected name %s", name)
+	}
+
+	long := MonitorName("web", strings.Repeat("a", 300))
+	other := MonitorName("web", strings.Repeat("a", 299)+"b")
+	if len(long) > maxNameLength || long == other {
+		t.Fatalf("expected long names to be shortened and unique, got %s and %s", long, other)
+	}
+}
@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewRelicAccountSpec defines the credentials used to talk to a New Relic account
+type NewRelicAccountSpec struct {
+	// SecretRef names a Secret in the same namespace holding the keys
+	// adminAPIKey, personalAPIKey, accountID and region
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NewRelicAccount is the Schema for the newrelicaccounts API
+// +kubebuilder:resource:path=newrelicaccounts,scope=Namespaced
+type NewRelicAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              NewRelicAccountSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NewRelicAccountList contains a list of NewRelicAccount
+type NewRelicAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []NewRelicAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NewRelicAccount{}, &NewRelicAccountList{})
+}
+
+// Additional Code
+
+// DefaultAccountName is the NewRelicAccount used by resources in a namespace that do not set accountRef
+const DefaultAccountName = "default"
+
+// Keys read from the Secret referenced by a NewRelicAccount
+const (
+	AdminAPIKeyKey    = "adminAPIKey"
+	PersonalAPIKeyKey = "personalAPIKey"
+	AccountIDKey      = "accountID"
+	RegionKey         = "region"
+)
@@ -0,0 +1,360 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NrqlAlertConditionSpec defines the desired state of NrqlAlertCondition
+type NrqlAlertConditionSpec struct {
+	// PolicyName is the name of the New Relic policy the condition belongs to
+	PolicyName string `json:"policyName,omitempty"`
+	// PolicyRef is the AlertPolicy resource the condition belongs to, it takes precedence over policyName
+	PolicyRef *ObjectReference `json:"policyRef,omitempty"`
+	// Query is the NRQL query whose result is compared with the thresholds
+	Query string `json:"query"`
+	// ValueFunction compares the query result as is with single_value or summed over the duration with sum
+	// +kubebuilder:validation:Enum=single_value;sum
+	ValueFunction string `json:"valueFunction,omitempty"`
+	// Critical opens a critical violation
+	Critical AlertThreshold `json:"critical"`
+	// Warning opens a warning violation
+	Warning *AlertThreshold `json:"warning,omitempty"`
+	// Signal controls how the query result is evaluated
+	Signal *NrqlAlertSignal `json:"signal,omitempty"`
+	// ViolationTimeLimitSeconds closes violations that stay open longer
+	// +kubebuilder:validation:Enum=3600;7200;14400;28800;43200;86400
+	ViolationTimeLimitSeconds int    `json:"violationTimeLimitSeconds,omitempty"`
+	RunbookURL                string `json:"runbookURL,omitempty"`
+	// Enabled defaults to true
+	Enabled    *bool                        `json:"enabled,omitempty"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// ImportID adopts the existing New Relic object with this ID instead of creating one
+	ImportID *string `json:"importID,omitempty"`
+	// AdoptExisting adopts an existing New Relic object with the same name instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// AlertThreshold opens a violation when the value crosses it
+type AlertThreshold struct {
+	// Operator defaults to above
+	// +kubebuilder:validation:Enum=above;below;equal
+	Operator string `json:"operator,omitempty"`
+	// +kubebuilder:validation:Type=number
+	Threshold float64 `json:"threshold"`
+	// DurationMinutes the threshold has to be crossed for
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=120
+	DurationMinutes int `json:"durationMinutes"`
+	// TimeFunction all requires every value in the duration to cross the threshold, any only one, defaults to all
+	// +kubebuilder:validation:Enum=all;any
+	TimeFunction string `json:"timeFunction,omitempty"`
+}
+
+// NrqlAlertSignal controls how the query result is evaluated
+type NrqlAlertSignal struct {
+	// EvaluationOffsetMinutes delays evaluation to wait for late data, defaults to 3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	EvaluationOffsetMinutes int `json:"evaluationOffsetMinutes,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NrqlAlertCondition is the Schema for the nrqlalertconditions API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=nrqlalertconditions,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type NrqlAlertCondition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              NrqlAlertConditionSpec `json:"spec"`
+	Status            Status                 `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NrqlAlertConditionList contains a list of NrqlAlertCondition
+type NrqlAlertConditionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []NrqlAlertCondition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NrqlAlertCondition{}, &NrqlAlertConditionList{})
+}
+
+// Additional Code
+
+var _ CRD = &NrqlAlertCondition{}
+
+// IsCreated specifies if the object has been created in new relic yet
+func (s *NrqlAlertCondition) IsCreated() bool {
+	return s.Status.IsCreated()
+}
+
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *NrqlAlertCondition) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *NrqlAlertCondition) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *NrqlAlertCondition) GetStatus() *Status {
+	return &s.Status
+}
+
+func (s *NrqlAlertCondition) policyID(ctx context.Context) (int, error) {
+	return resolvePolicyID(ctx, s, s.Spec.PolicyName, s.Spec.PolicyRef)
+}
+
+// UsesAlertPolicy reports if the condition refers to the AlertPolicy resource namespace/name
+func (s *NrqlAlertCondition) UsesAlertPolicy(namespace string, name string) bool {
+	return s.Spec.PolicyRef != nil && s.Spec.PolicyRef.refersTo(s.GetNamespace(), namespace, name)
+}
+
+func (s *NrqlAlertCondition) toNewRelic() (*alerts.NrqlCondition, error) {
+	if s.Spec.Query == "" {
+		return nil, errors.New("query is required")
+	}
+
+	sinceValue := 3
+	if s.Spec.Signal != nil && s.Spec.Signal.EvaluationOffsetMinutes != 0 {
+		sinceValue = s.Spec.Signal.EvaluationOffsetMinutes
+	}
+
+	valueFunction := alerts.ValueFunctionTypes.SingleValue
+	if s.Spec.ValueFunction != "" {
+		valueFunction = alerts.ValueFunctionType(s.Spec.ValueFunction)
+	}
+
+	data := alerts.NrqlCondition{
+		Name: s.GetName(),
+		Type: "static",
+		Nrql: alerts.NrqlQuery{
+			Query:      s.Spec.Query,
+			SinceValue: strconv.Itoa(sinceValue),
+		},
+		ValueFunction:       valueFunction,
+		RunbookURL:          s.Spec.RunbookURL,
+		ViolationCloseTimer: s.Spec.ViolationTimeLimitSeconds,
+		Enabled:             s.Spec.Enabled == nil || *s.Spec.Enabled,
+		Terms:               []alerts.ConditionTerm{s.Spec.Critical.toNewRelic(alerts.PriorityTypes.Critical)},
+	}
+
+	if s.Spec.Warning != nil {
+		data.Terms = append(data.Terms, s.Spec.Warning.toNewRelic(alerts.PriorityTypes.Warning))
+	}
+
+	for _, term := range data.Terms {
+		if term.Duration < 1 {
+			return nil, errors.New("threshold durationMinutes must be at least 1")
+		}
+	}
+
+	if s.Status.ID != nil {
+		data.ID = int(*s.Status.GetID())
+	}
+	return &data, nil
+}
+
+func (t AlertThreshold) toNewRelic(priority alerts.PriorityType) alerts.ConditionTerm {
+	term := alerts.ConditionTerm{
+		Priority:     priority,
+		Operator:     alerts.OperatorTypes.Above,
+		Threshold:    t.Threshold,
+		Duration:     t.DurationMinutes,
+		TimeFunction: alerts.TimeFunctionTypes.All,
+	}
+
+	if t.Operator != "" {
+		term.Operator = alerts.OperatorType(t.Operator)
+	}
+	if t.TimeFunction != "" {
+		term.TimeFunction = alerts.TimeFunctionType(t.TimeFunction)
+	}
+	return term
+}
+
+// sortedTerms returns the terms ordered by priority so ordering does not count as drift
+func sortedTerms(terms []alerts.ConditionTerm) []alerts.ConditionTerm {
+	result := append([]alerts.ConditionTerm{}, terms...)
+	sort.Slice(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+	return result
+}
+
+// Adopt takes over an existing condition of the policy matching importID, or matching the name when adoptExisting is set
+func (s *NrqlAlertCondition) Adopt(ctx context.Context) bool {
+	if s.Spec.ImportID == nil && !s.Spec.AdoptExisting {
+		return false
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if s.Spec.ImportID != nil {
+		id, err := parseImportID(*s.Spec.ImportID)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		data, err := GetClient(ctx).Alerts.GetNrqlCondition(policyID, id)
+		if isNotFound(err) {
+			err = importNotFound(*s.Spec.ImportID)
+		}
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		s.Status.adopted(ctx, strconv.Itoa(data.ID))
+		return false
+	}
+
+	items, err := GetClient(ctx).Alerts.ListNrqlConditions(policyID)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	ids := []int{}
+	for _, item := range items {
+		if item.Name == s.GetName() {
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 1 {
+		return s.Status.HandleOnError(ctx, ambiguousName(s.GetName(), len(ids)))
+	}
+	if len(ids) == 1 {
+		s.Status.adopted(ctx, strconv.Itoa(ids[0]))
+	}
+	return false
+}
+
+// Create in newrelic
+func (s *NrqlAlertCondition) Create(ctx context.Context) bool {
+	input, err := s.toNewRelic()
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	data, err := GetClient(ctx).Alerts.CreateNrqlCondition(policyID, *input)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	s.Status.SetID(data.ID)
+	s.Status.Hash = hash
+	return false
+}
+
+// Delete in newrelic
+func (s *NrqlAlertCondition) Delete(ctx context.Context) bool {
+	logger := GetLogger(ctx)
+
+	id := s.Status.GetID()
+	if id == nil {
+		logger.Info("object does not exist")
+		return false
+	}
+
+	// conditions are removed along with their policy
+	_, err := GetClient(ctx).Alerts.DeleteNrqlCondition(*id)
+	if isNotFound(err) {
+		return false
+	}
+	return s.Status.HandleOnError(ctx, err)
+}
+
+// Update object in newrelic
+func (s *NrqlAlertCondition) Update(ctx context.Context) bool {
+	input, err := s.toNewRelic()
+	if s.Status.HandleOnError(ctx, invalidSpec(err)) {
+		return true
+	}
+
+	policyID, err := s.policyID(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	current, err := GetClient(ctx).Alerts.GetNrqlCondition(policyID, input.ID)
+	if isNotFound(err) {
+		// conditions can not be moved, one that still exists belongs to the previous policy
+		_, err = GetClient(ctx).Alerts.DeleteNrqlCondition(input.ID)
+		if isNotFound(err) {
+			s.Status.markDeletedRemote(ctx)
+		} else if s.Status.HandleOnError(ctx, err) {
+			return true
+		} else {
+			GetLogger(ctx).Info("moving condition to another policy", "policy", policyID)
+			s.Status.ID = nil
+		}
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	fields := drift{}
+	fields.check("name", input.Name, current.Name)
+	fields.check("query", input.Nrql, current.Nrql)
+	fields.check("valueFunction", input.ValueFunction, current.ValueFunction)
+	fields.check("thresholds", sortedTerms(input.Terms), sortedTerms(current.Terms))
+	if input.ViolationCloseTimer != 0 {
+		fields.check("violationTimeLimitSeconds", input.ViolationCloseTimer, current.ViolationCloseTimer)
+	}
+	fields.check("runbookURL", input.RunbookURL, current.RunbookURL)
+	fields.check("enabled", input.Enabled, current.Enabled)
+
+	if len(fields) > 0 {
+		_, err = GetClient(ctx).Alerts.UpdateNrqlCondition(*input)
+		if s.Status.HandleOnError(ctx, err) {
+			return true
+		}
+
+		if !s.Status.specChanged(hash) {
+			s.Status.markDrift(ctx, fields)
+		} else {
+			s.Status.markInSync()
+		}
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
+	return false
+}
@@ -0,0 +1,177 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNrqlAlertConditionLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	ctx := WithClient(context.TODO(), account.Client())
+
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := &NrqlAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "errors"},
+		Spec: NrqlAlertConditionSpec{
+			PolicyName: "policy",
+			Query:      "SELECT count(*) FROM TransactionError",
+			Critical:   AlertThreshold{Threshold: 10, DurationMinutes: 5},
+			Warning:    &AlertThreshold{Operator: "above", Threshold: 5, DurationMinutes: 5, TimeFunction: "any"},
+		},
+	}
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+
+	data, err := account.GetNrqlCondition(policy.ID, *condition.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.Enabled || data.Nrql.SinceValue != "3" || data.ValueFunction != alerts.ValueFunctionTypes.SingleValue {
+		t.Fatalf("unexpected defaults %+v", data)
+	}
+	if len(data.Terms) != 2 || data.Terms[0].Priority != alerts.PriorityTypes.Critical || data.Terms[0].TimeFunction != alerts.TimeFunctionTypes.All {
+		t.Fatalf("unexpected terms %+v", data.Terms)
+	}
+
+	disabled := false
+	condition.Spec.Enabled = &disabled
+	condition.Spec.Warning = nil
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	data, err = account.GetNrqlCondition(policy.ID, data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Enabled || len(data.Terms) != 1 {
+		t.Fatalf("expected condition to be updated, got %+v", data)
+	}
+
+	if condition.Delete(ctx) {
+		t.Fatalf("delete failed: %s", condition.Status.Info)
+	}
+	if _, err = account.GetNrqlCondition(policy.ID, data.ID); err == nil {
+		t.Fatal("expected condition to be deleted")
+	}
+}
+
+func TestNrqlAlertConditionDrift(t *testing.T) {
+	account := fake.NewAccount()
+	recorder := record.NewFakeRecorder(10)
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	condition := &NrqlAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "errors"},
+		Spec: NrqlAlertConditionSpec{
+			PolicyName: "policy",
+			Query:      "SELECT count(*) FROM TransactionError",
+			Critical:   AlertThreshold{Threshold: 10, DurationMinutes: 5},
+		},
+	}
+	ctx := WithRecorder(WithClient(context.TODO(), account.Client()), recorder, condition)
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+
+	data, err := account.GetNrqlCondition(policy.ID, *condition.Status.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.Terms[0].Threshold = 100
+	if _, err = account.UpdateNrqlCondition(*data); err != nil {
+		t.Fatal(err)
+	}
+
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	data, err = account.GetNrqlCondition(policy.ID, data.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Terms[0].Threshold != 10 {
+		t.Fatalf("expected threshold to be repaired, got %v", data.Terms[0].Threshold)
+	}
+	if !condition.Status.IsConditionTrue(ConditionDrifted) || len(recorder.Events) != 1 {
+		t.Fatalf("expected drift to be recorded, got %+v", condition.Status.Conditions)
+	}
+}
+
+func TestNrqlAlertConditionPolicyRef(t *testing.T) {
+	account := fake.NewAccount()
+	first, err := account.CreatePolicy(alerts.Policy{Name: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}}
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s, policy)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	condition := &NrqlAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: "errors", Namespace: "default"},
+		Spec: NrqlAlertConditionSpec{
+			PolicyRef: &ObjectReference{Name: "first"},
+			Query:     "SELECT count(*) FROM TransactionError",
+			Critical:  AlertThreshold{Threshold: 10, DurationMinutes: 5},
+		},
+	}
+
+	if !condition.Create(ctx) {
+		t.Fatal("expected create to wait for the policy")
+	}
+	if c := condition.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotReady || condition.Status.Phase != PhasePending {
+		t.Fatalf("expected to wait for the policy, got %+v", condition.Status.Conditions)
+	}
+
+	policy.Status.SetID(first.ID)
+	if err := kube.Update(context.TODO(), policy); err != nil {
+		t.Fatal(err)
+	}
+
+	if condition.Create(ctx) {
+		t.Fatalf("create failed: %s", condition.Status.Info)
+	}
+	id := *condition.Status.GetID()
+
+	// conditions can not be moved so changing the policy replaces it
+	second, err := account.CreatePolicy(alerts.Policy{Name: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition.Spec.PolicyRef = nil
+	condition.Spec.PolicyName = "second"
+	if condition.Update(ctx) {
+		t.Fatalf("update failed: %s", condition.Status.Info)
+	}
+
+	if items, _ := account.ListNrqlConditions(first.ID); len(items) != 0 {
+		t.Fatalf("expected condition to be removed from the first policy, got %+v", items)
+	}
+	if _, err = account.GetNrqlCondition(second.ID, *condition.Status.GetID()); err != nil || *condition.Status.GetID() == id {
+		t.Fatalf("expected condition to be created in the second policy, got %v", err)
+	}
+}
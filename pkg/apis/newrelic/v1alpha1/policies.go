@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+)
+
+// resolvePolicyID returns the ID of the AlertPolicy resource ref made by from, or of the New Relic policy named name
+func resolvePolicyID(ctx context.Context, from CRD, name string, ref *ObjectReference) (int, error) {
+	if err := validatePolicy(name, ref); err != nil {
+		return 0, err
+	}
+
+	if ref == nil {
+		id, err := findPolicyID(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+		return *id, nil
+	}
+
+	return resolveID(ctx, from, "AlertPolicy", *ref, &AlertPolicy{})
+}
+
+// validatePolicy checks that a policy is named or referenced
+func validatePolicy(name string, ref *ObjectReference) error {
+	if ref == nil && name == "" {
+		return invalidSpec(errors.New("policyName or policyRef is required"))
+	}
+	if ref != nil && ref.Name == "" {
+		return invalidSpec(errors.New("references require a name"))
+	}
+	return nil
+}
+
+// findPolicyID returns the ID of the only New Relic policy named name
+func findPolicyID(ctx context.Context, name string) (*int, error) {
+	logger := GetLogger(ctx)
+	policies, err := GetClient(ctx).Alerts.ListPolicies(&alerts.ListPoliciesParams{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var id *int
+	for _, item := range policies {
+		if item.Name == name {
+			if id != nil {
+				for _, item := range policies {
+					if item.Name == name {
+						logger.V(1).Info(fmt.Sprintf("duplicate policies %s %d", item.Name, item.ID))
+					}
+				}
+				err = fmt.Errorf("expected a policy search by name to only return 1 result, but found multiple for %s", name)
+				return nil, err
+			}
+			value := item.ID
+			id = &value
+		}
+	}
+
+	if id != nil {
+		return id, nil
+	}
+
+	return nil, missingDependency(fmt.Errorf("unable to find policy %s", name))
+}
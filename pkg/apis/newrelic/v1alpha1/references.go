@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ObjectReference points at another resource managed by the operator
+type ObjectReference struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the referring resource
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (r ObjectReference) key(namespace string) types.NamespacedName {
+	if r.Namespace != "" {
+		namespace = r.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: r.Name}
+}
+
+// refersTo reports if the reference made from namespace points at namespace/name
+func (r ObjectReference) refersTo(from string, namespace string, name string) bool {
+	return r.key(from) == types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+// resolveID reads the resource ref made by from points at into obj and returns its New Relic ID,
+// both have to be synced with the same New Relic account for the ID to mean anything to from
+func resolveID(ctx context.Context, from CRD, kind string, ref ObjectReference, obj CRD) (int, error) {
+	kube := getKubeClient(ctx)
+	if kube == nil {
+		return 0, fmt.Errorf("unable to read %s resources without a Kubernetes client", kind)
+	}
+	if ref.Name == "" {
+		return 0, invalidSpec(errors.New("references require a name"))
+	}
+
+	key := ref.key(from.GetNamespace())
+	err := kube.Get(ctx, key, obj)
+	if apierrors.IsNotFound(err) {
+		return 0, missingDependency(fmt.Errorf("unable to find %s %s", kind, key))
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := sameAccount(ctx, from, obj); err != nil {
+		return 0, fmt.Errorf("%s %s %w", kind, key, err)
+	}
+
+	id := obj.GetStatus().GetID()
+	if id == nil {
+		return 0, dependencyNotReady(fmt.Errorf("waiting for %s %s to be created in New Relic", kind, key))
+	}
+	return *id, nil
+}
+
+// sameAccount checks that a and b use the credentials of the same New Relic account
+func sameAccount(ctx context.Context, a CRD, b CRD) error {
+	accounts := getAccounts(ctx)
+	if accounts == nil || (a.GetNamespace() == b.GetNamespace() && accountName(a) == accountName(b)) {
+		return nil
+	}
+
+	credentials, err := accounts.CredentialsFor(ctx, a.GetNamespace(), a.GetAccountRef())
+	if err != nil {
+		return err
+	}
+	other, err := accounts.CredentialsFor(ctx, b.GetNamespace(), b.GetAccountRef())
+	if err != nil {
+		return err
+	}
+	if credentials != other {
+		return invalidSpec(errors.New("is synced with another New Relic account"))
+	}
+	return nil
+}
+
+// accountName returns the name of the NewRelicAccount obj uses
+func accountName(obj CRD) string {
+	if ref := obj.GetAccountRef(); ref != nil {
+		return ref.Name
+	}
+	return DefaultAccountName
+}
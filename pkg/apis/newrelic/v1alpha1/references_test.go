@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testAccounts maps the namespace/name of an account to its API key
+type testAccounts map[string]string
+
+func (a testAccounts) CredentialsFor(ctx context.Context, namespace string, ref *corev1.LocalObjectReference) (newrelic.Credentials, error) {
+	name := DefaultAccountName
+	if ref != nil {
+		name = ref.Name
+	}
+	return newrelic.Credentials{AdminAPIKey: a[namespace+"/"+name]}, nil
+}
+
+func TestResolveIDAccounts(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &AlertChannel{ObjectMeta: metav1.ObjectMeta{Name: "oncall", Namespace: "shared"}}
+	channel.Status.SetID(1)
+	kube := clientfake.NewFakeClientWithScheme(s, channel)
+	policy := &AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "team"}}
+	ref := ObjectReference{Name: "oncall", Namespace: "shared"}
+
+	accounts := testAccounts{"team/default": "key", "shared/default": "key", "team/other": "other-key"}
+	ctx := WithAccounts(WithKubeClient(WithClient(context.TODO(), fake.New()), kube), accounts)
+
+	id, err := resolveID(ctx, policy, "AlertChannel", ref, &AlertChannel{})
+	if err != nil || id != 1 {
+		t.Fatalf("expected a reference to a namespace with the same credentials to resolve, got %d %v", id, err)
+	}
+
+	policy.Spec.AccountRef = &corev1.LocalObjectReference{Name: "other"}
+	_, err = resolveID(ctx, policy, "AlertChannel", ref, &AlertChannel{})
+	if !IsInvalidSpec(err) {
+		t.Fatalf("expected a reference into another account to be an invalid spec, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecureCredentialSpec defines the desired state of SecureCredential
+type SecureCredentialSpec struct {
+	// Key is used as $secure.KEY in monitor scripts, defaults to the resource name in upper case with dashes and dots replaced by underscores
+	// +kubebuilder:validation:Pattern=`^[A-Z0-9_]{1,64}$`
+	Key         string `json:"key,omitempty"`
+	Description string `json:"description,omitempty"`
+	// ValueFrom reads the value of the credential from a Secret
+	ValueFrom  SecureCredentialSource       `json:"valueFrom"`
+	AccountRef *corev1.LocalObjectReference `json:"accountRef,omitempty"`
+	// DeletionPolicy set to Orphan keeps the New Relic object when this resource is deleted
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	// AdoptExisting adopts an existing New Relic secure credential with the same key instead of creating one
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// SecureCredentialSource selects the Secret key holding the value of a secure credential
+type SecureCredentialSource struct {
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecureCredential is the Schema for the securecredentials API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=securecredentials,scope=Namespaced
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Key",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type SecureCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              SecureCredentialSpec   `json:"spec"`
+	Status            SecureCredentialStatus `json:"status,omitempty"`
+}
+
+// SecureCredentialStatus is the shared status with the configuration a SecureCredential was synced with
+type SecureCredentialStatus struct {
+	Status `json:",inline"`
+	// ConfigurationHash is the digest of the description and the version of the Secret the value was last read at
+	ConfigurationHash []byte `json:"configurationHash,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecureCredentialList contains a list of SecureCredential
+type SecureCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []SecureCredential `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecureCredential{}, &SecureCredentialList{})
+}
+
+// Additional Code
+
+var _ CRD = &SecureCredential{}
+
+// secureCredentialKey is the format New Relic accepts for keys
+var secureCredentialKey = regexp.MustCompile(`^[A-Z0-9_]{1,64}$`)
+
+// IsCreated specifies if the object has been created in new relic yet
+func (s *SecureCredential) IsCreated() bool {
+	return s.Status.IsCreated()
+}
+
+// GetAccountRef returns the NewRelicAccount this object is managed with
+func (s *SecureCredential) GetAccountRef() *corev1.LocalObjectReference {
+	return s.Spec.AccountRef
+}
+
+// GetDeletionPolicy returns what happens to the New Relic object on deletion
+func (s *SecureCredential) GetDeletionPolicy() DeletionPolicy {
+	return s.Spec.DeletionPolicy
+}
+
+// GetStatus returns the shared status of the object
+func (s *SecureCredential) GetStatus() *Status {
+	return &s.Status.Status
+}
+
+// UsesSecret reports if the value is read from the Secret
+func (s *SecureCredential) UsesSecret(name string) bool {
+	return s.Spec.ValueFrom.SecretKeyRef != nil && s.Spec.ValueFrom.SecretKeyRef.Name == name
+}
+
+// key returns the key of the credential in New Relic
+func (s *SecureCredential) key() string {
+	if s.Spec.Key != "" {
+		return s.Spec.Key
+	}
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(s.GetName()))
+}
+
+// validate checks the spec without calling New Relic or reading the Secret
+func (s *SecureCredential) validate() error {
+	if !secureCredentialKey.MatchString(s.key()) {
+		return fmt.Errorf("key %s may only hold up to 64 upper case letters, digits and underscores", s.key())
+	}
+	ref := s.Spec.ValueFrom.SecretKeyRef
+	if ref == nil || ref.Name == "" || ref.Key == "" {
+		return errors.New("valueFrom.secretKeyRef requires a name and a key")
+	}
+	return nil
+}
+
+// value reads the value of the credential from its Secret, it also returns the resource version of the Secret
+func (s *SecureCredential) value(ctx context.Context) (string, string, error) {
+	if err := s.validate(); err != nil {
+		return "", "", invalidSpec(err)
+	}
+
+	value, version, err := readSecretKeyVersion(ctx, s.GetNamespace(), s.Spec.ValueFrom.SecretKeyRef)
+	if err == nil && value == "" {
+		err = invalidSpec(fmt.Errorf("key %s of Secret %s is empty", s.Spec.ValueFrom.SecretKeyRef.Key, s.Spec.ValueFrom.SecretKeyRef.Name))
+	}
+	return value, version, err
+}
+
+// configurationHash covers the description and the version of the Secret the value was read at, so rotating the
+// Secret updates the credential. The value itself is left out, a digest of it in the status could be guessed offline.
+func (s *SecureCredential) configurationHash(version string) ([]byte, error) {
+	ref := s.Spec.ValueFrom.SecretKeyRef
+	return hashSpec([]string{ref.Name + "/" + ref.Key + "@" + version, s.Spec.Description})
+}
+
+// Adopt takes over an existing secure credential with the same key when adoptExisting is set
+func (s *SecureCredential) Adopt(ctx context.Context) bool {
+	if !s.Spec.AdoptExisting {
+		return false
+	}
+
+	_, err := GetClient(ctx).Synthetics.GetSecureCredential(s.key())
+	if isNotFound(err) {
+		return false
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	s.Status.adopted(ctx, s.key())
+	return false
+}
+
+// Create in newrelic
+func (s *SecureCredential) Create(ctx context.Context) bool {
+	value, version, err := s.value(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	configurationHash, err := s.configurationHash(version)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	key := s.key()
+	_, err = GetClient(ctx).Synthetics.AddSecureCredential(key, value, s.Spec.Description)
+	if s.Status.HandleOnError(ctx, redact(err, []string{value})) {
+		return true
+	}
+
+	s.Status.ID = &key
+
+	s.Status.Hash = hash
+	s.Status.ConfigurationHash = configurationHash
+	return false
+}
+
+// Delete in newrelic
+func (s *SecureCredential) Delete(ctx context.Context) bool {
+	logger := GetLogger(ctx)
+
+	if s.Status.ID == nil {
+		logger.Info("object does not exist")
+		return false
+	}
+
+	err := GetClient(ctx).Synthetics.DeleteSecureCredential(*s.Status.ID)
+	if isNotFound(err) {
+		return false
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	return false
+}
+
+// Update object in newrelic
+func (s *SecureCredential) Update(ctx context.Context) bool {
+	value, version, err := s.value(ctx)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	hash, err := hashSpec(s.Spec)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	configurationHash, err := s.configurationHash(version)
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	if *s.Status.ID != s.key() {
+		return s.rename(ctx)
+	}
+
+	current, err := GetClient(ctx).Synthetics.GetSecureCredential(*s.Status.ID)
+	if isNotFound(err) {
+		s.Status.markDeletedRemote(ctx)
+		return s.Create(ctx)
+	}
+	if s.Status.HandleOnError(ctx, err) {
+		return true
+	}
+
+	// New Relic never returns the value, a changed value is only noticed through the Secret
+	fields := drift{}
+	fields.check("description", s.Spec.Description, current.Description)
+
+	if len(fields) > 0 || !bytes.Equal(configurationHash, s.Status.ConfigurationHash) {
+		_, err = GetClient(ctx).Synthetics.UpdateSecureCredential(*s.Status.ID, value, s.Spec.Description)
+		if s.Status.HandleOnError(ctx, redact(err, []string{value})) {
+			return true
+		}
+	}
+
+	if len(fields) > 0 && !s.Status.specChanged(hash) {
+		s.Status.markDrift(ctx, fields)
+	} else {
+		s.Status.markInSync()
+	}
+
+	s.Status.Hash = hash
+	s.Status.ConfigurationHash = configurationHash
+	return false
+}
+
+// rename creates the credential under its new key before deleting the old one so scripts keep working
+func (s *SecureCredential) rename(ctx context.Context) bool {
+	old := *s.Status.ID
+	if s.Create(ctx) {
+		return true
+	}
+
+	err := GetClient(ctx).Synthetics.DeleteSecureCredential(old)
+	if isNotFound(err) {
+		err = nil
+	}
+	return s.Status.HandleOnErrorMessage(ctx, err, fmt.Sprintf("failed to delete the old key %s", old))
+}
@@ -0,0 +1,108 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecureCredentialLifecycle(t *testing.T) {
+	account := fake.NewAccount()
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := clientfake.NewFakeClientWithScheme(s)
+	ctx := WithKubeClient(WithClient(context.TODO(), account.Client()), kube)
+
+	credential := &SecureCredential{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "team"},
+		Spec: SecureCredentialSpec{
+			Description: "token of the status API",
+			ValueFrom: SecureCredentialSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "tokens"}, Key: "api",
+			}},
+		},
+	}
+	if !credential.UsesSecret("tokens") || credential.UsesSecret("other") {
+		t.Fatal("expected the credential to use the tokens Secret only")
+	}
+
+	if !credential.Create(ctx) {
+		t.Fatal("expected create to wait for the Secret")
+	}
+	if c := credential.Status.GetCondition(ConditionDependenciesResolved); c == nil || c.Reason != ReasonDependencyNotFound {
+		t.Fatalf("expected missing Secret, got %+v", credential.Status.Conditions)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tokens", Namespace: "team"},
+		Data:       map[string][]byte{"api": []byte("first")},
+	}
+	if err := kube.Create(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if credential.Create(ctx) {
+		t.Fatalf("create failed: %s", credential.Status.Info)
+	}
+	if *credential.Status.ID != "API_TOKEN" || account.SecureCredentialValue("API_TOKEN") != "first" {
+		t.Fatalf("expected API_TOKEN to be created from the Secret, got %v", *credential.Status.ID)
+	}
+	if hash, _ := hashSpec([]string{"first", credential.Spec.Description}); bytes.Equal(hash, credential.Status.ConfigurationHash) {
+		t.Fatal("expected the value to be left out of the configuration hash")
+	}
+
+	secret.Data["api"] = []byte("second")
+	if err := kube.Update(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if credential.Update(ctx) {
+		t.Fatalf("update failed: %s", credential.Status.Info)
+	}
+	if account.SecureCredentialValue("API_TOKEN") != "second" || credential.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatal("expected the rotated value to be applied without drift")
+	}
+
+	if _, err := account.UpdateSecureCredential("API_TOKEN", "second", "edited"); err != nil {
+		t.Fatal(err)
+	}
+	if credential.Update(ctx) {
+		t.Fatalf("update failed: %s", credential.Status.Info)
+	}
+	current, err := account.GetSecureCredential("API_TOKEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Description != "token of the status API" || !credential.Status.IsConditionTrue(ConditionDrifted) {
+		t.Fatal("expected the description to be reverted as drift")
+	}
+
+	credential.Spec.Key = "STATUS_TOKEN"
+	if credential.Update(ctx) {
+		t.Fatalf("update failed: %s", credential.Status.Info)
+	}
+	if *credential.Status.ID != "STATUS_TOKEN" || account.SecureCredentialValue("STATUS_TOKEN") != "second" {
+		t.Fatal("expected the credential to be created under the new key")
+	}
+	if _, err := account.GetSecureCredential("API_TOKEN"); err == nil {
+		t.Fatal("expected the old key to be deleted")
+	}
+
+	if credential.Delete(ctx) {
+		t.Fatalf("delete failed: %s", credential.Status.Info)
+	}
+	if _, err := account.GetSecureCredential("STATUS_TOKEN"); err == nil {
+		t.Fatal("expected the credential to be deleted")
+	}
+
+	// a credential removed in New Relic must not hold up the finalizer
+	if credential.Delete(ctx) {
+		t.Fatalf("expected deleting a missing credential to succeed: %s", credential.Status.Info)
+	}
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// readConfigMapKey returns the value of a ConfigMap key in namespace, an optional missing key is returned empty
+func readConfigMapKey(ctx context.Context, namespace string, ref *corev1.ConfigMapKeySelector) (string, error) {
+	kube := getKubeClient(ctx)
+	if kube == nil {
+		return "", errors.New("unable to read ConfigMaps without a Kubernetes client")
+	}
+
+	optional := ref.Optional != nil && *ref.Optional
+	configMap := &corev1.ConfigMap{}
+	err := kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap)
+	if apierrors.IsNotFound(err) {
+		if optional {
+			return "", nil
+		}
+		return "", missingDependency(fmt.Errorf("unable to find ConfigMap %s", ref.Name))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if value, ok := configMap.Data[ref.Key]; ok {
+		return value, nil
+	}
+	if value, ok := configMap.BinaryData[ref.Key]; ok {
+		return string(value), nil
+	}
+	if optional {
+		return "", nil
+	}
+	return "", missingDependency(fmt.Errorf("key %s not found in ConfigMap %s", ref.Key, ref.Name))
+}
+
+// readSecretKey returns the value of a Secret key in namespace, an optional missing key is returned empty
+func readSecretKey(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	value, _, err := readSecretKeyVersion(ctx, namespace, ref)
+	return value, err
+}
+
+// readSecretKeyVersion is readSecretKey that also returns the resource version of the Secret, it lets callers notice
+// a rotated value without keeping a digest of it. The version is empty when an optional Secret is missing.
+func readSecretKeyVersion(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) (string, string, error) {
+	kube := getKubeClient(ctx)
+	if kube == nil {
+		return "", "", errors.New("unable to read Secrets without a Kubernetes client")
+	}
+
+	optional := ref.Optional != nil && *ref.Optional
+	secret := &corev1.Secret{}
+	err := kube.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret)
+	if apierrors.IsNotFound(err) {
+		if optional {
+			return "", "", nil
+		}
+		return "", "", missingDependency(fmt.Errorf("unable to find Secret %s", ref.Name))
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if value, ok := secret.Data[ref.Key]; ok {
+		return string(value), secret.ResourceVersion, nil
+	}
+	if optional {
+		return "", secret.ResourceVersion, nil
+	}
+	return "", "", missingDependency(fmt.Errorf("key %s not found in Secret %s", ref.Key, ref.Name))
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"strconv"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -19,12 +21,16 @@ type Data struct {
 }
 
 type CRD interface {
+	metav1.Object
+	runtime.Object
+	Adopt(context.Context) bool
 	Create(context.Context) bool
 	Update(context.Context) bool
 	Delete(context.Context) bool
 	IsCreated() bool
-	GetDeletionTimestamp() *metav1.Time
-	SetFinalizers([]string)
+	GetAccountRef() *corev1.LocalObjectReference
+	GetDeletionPolicy() DeletionPolicy
+	GetStatus() *Status
 }
 
 type SpecInterface interface {
@@ -43,6 +49,13 @@ type Status struct {
 	ID   *string `json:"id,omitempty"`
 	Info string  `json:"info,omitempty"`
 	Hash []byte  `json:"hash,omitempty"`
+	// Phase is a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation last processed by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastSyncTime is when the object was last successfully synced with New Relic
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	Conditions   []Condition  `json:"conditions,omitempty"`
 }
 
 // IsCreated let us know if the dashboard exists
@@ -66,7 +79,7 @@ func (s *Status) SetID(id int) {
 // HandleOnErrorMessage returns true if an error had occured
 func (s *Status) HandleOnErrorMessage(ctx context.Context, err error, msg string) bool {
 	if err != nil && msg != "" {
-		err = fmt.Errorf("%s %v", msg, err)
+		err = fmt.Errorf("%s %w", msg, err)
 	}
 
 	return s.HandleOnError(ctx, err)
@@ -79,6 +92,7 @@ func (s *Status) HandleOnError(ctx context.Context, err error) bool {
 	if err != nil {
 		s.Info = err.Error()
 		logger.Info(s.Info)
+		s.markFailed(err)
 		return true
 	}
 	return false
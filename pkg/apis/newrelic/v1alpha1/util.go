@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	"errors"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// isNotFound reports if New Relic answered that the object does not exist
+func isNotFound(err error) bool {
+	var notFound *nrErrors.NotFound
+	return errors.As(err, &notFound)
+}
+
+func containsInt(items []int, value int) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt64(items []int64, value int64) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
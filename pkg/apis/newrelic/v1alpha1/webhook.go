@@ -0,0 +1,261 @@
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// The validating webhook rejects specs that can never be synced when they are applied,
+// checks that need New Relic or other resources are left to the controllers. Monitor locations are
+// the exception, they are checked against the cached locations of the account through ValidateLocations.
+// The mutating webhook writes the defaults of Monitors into their spec.
+
+var (
+	_ admission.Validator = &AlertChannel{}
+	_ admission.Validator = &AlertCondition{}
+	_ admission.Validator = &AlertPolicy{}
+	_ admission.Validator = &Dashboard{}
+	_ admission.Validator = &InfraAlertCondition{}
+	_ admission.Validator = &Monitor{}
+	_ admission.Validator = &MonitorSet{}
+	_ admission.Validator = &NewRelicAccount{}
+	_ admission.Validator = &NrqlAlertCondition{}
+	_ admission.Validator = &SecureCredential{}
+
+	_ admission.Defaulter = &Monitor{}
+)
+
+// validateUpdate validates obj unless it is being deleted or its spec is the one of old, objects
+// stored before a check was added can then still have their status and finalizers written
+func validateUpdate(obj metav1.Object, old runtime.Object, validate func() error) error {
+	if obj.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	if old != nil && reflect.DeepEqual(specOf(obj), specOf(old)) {
+		return nil
+	}
+	return validate()
+}
+
+// specOf returns the Spec field of a resource
+func specOf(obj interface{}) interface{} {
+	spec := reflect.Indirect(reflect.ValueOf(obj)).FieldByName("Spec")
+	if !spec.IsValid() {
+		return nil
+	}
+	return spec.Interface()
+}
+
+// ValidateCreate checks the spec of a new AlertChannel
+func (s *AlertChannel) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed AlertChannel
+func (s *AlertChannel) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every AlertChannel to be deleted
+func (s *AlertChannel) ValidateDelete() error {
+	return nil
+}
+
+func (s *AlertCondition) validate() error {
+	if err := validatePolicy(s.Spec.PolicyName, s.Spec.PolicyRef); err != nil {
+		return err
+	}
+	_, err := s.toNewRelic(nil)
+	return err
+}
+
+// ValidateCreate checks the spec of a new AlertCondition
+func (s *AlertCondition) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed AlertCondition
+func (s *AlertCondition) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every AlertCondition to be deleted
+func (s *AlertCondition) ValidateDelete() error {
+	return nil
+}
+
+func (s *AlertPolicy) validate() error {
+	_, err := s.toNewRelic()
+	return err
+}
+
+// ValidateCreate checks the spec of a new AlertPolicy
+func (s *AlertPolicy) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed AlertPolicy
+func (s *AlertPolicy) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every AlertPolicy to be deleted
+func (s *AlertPolicy) ValidateDelete() error {
+	return nil
+}
+
+// ValidateCreate checks the spec of a new Dashboard
+func (s *Dashboard) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed Dashboard
+func (s *Dashboard) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every Dashboard to be deleted
+func (s *Dashboard) ValidateDelete() error {
+	return nil
+}
+
+func (s *InfraAlertCondition) validate() error {
+	if err := validatePolicy(s.Spec.PolicyName, s.Spec.PolicyRef); err != nil {
+		return err
+	}
+	_, err := s.toNewRelic(0)
+	return err
+}
+
+// ValidateCreate checks the spec of a new InfraAlertCondition
+func (s *InfraAlertCondition) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed InfraAlertCondition
+func (s *InfraAlertCondition) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every InfraAlertCondition to be deleted
+func (s *InfraAlertCondition) ValidateDelete() error {
+	return nil
+}
+
+// ValidateCreate checks the spec of a new Monitor
+func (s *Monitor) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed Monitor
+func (s *Monitor) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every Monitor to be deleted
+func (s *Monitor) ValidateDelete() error {
+	return nil
+}
+
+// ValidateLocations checks the locations of a new or changed Monitor against the locations of the account in ctx,
+// old is nil for a new Monitor. Only unknown and ambiguous locations are rejected, when the locations can not be
+// listed the check is left to the controller.
+func (s *Monitor) ValidateLocations(ctx context.Context, old runtime.Object) error {
+	return validateUpdate(s, old, func() error {
+		names := []string{}
+		for _, location := range s.Spec.Locations {
+			if location != nil {
+				names = append(names, *location)
+			}
+		}
+		if len(names) == 0 {
+			return nil
+		}
+
+		_, err := resolveLocations(ctx, names)
+		if IsInvalidSpec(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+// ValidateCreate checks the spec of a new MonitorSet
+func (s *MonitorSet) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed MonitorSet
+func (s *MonitorSet) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every MonitorSet to be deleted
+func (s *MonitorSet) ValidateDelete() error {
+	return nil
+}
+
+func (s *NewRelicAccount) validate() error {
+	if s.Spec.SecretRef.Name == "" {
+		return errors.New("secretRef.name is required")
+	}
+	return nil
+}
+
+// ValidateCreate checks the spec of a new NewRelicAccount
+func (s *NewRelicAccount) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed NewRelicAccount
+func (s *NewRelicAccount) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every NewRelicAccount to be deleted
+func (s *NewRelicAccount) ValidateDelete() error {
+	return nil
+}
+
+func (s *NrqlAlertCondition) validate() error {
+	if err := validatePolicy(s.Spec.PolicyName, s.Spec.PolicyRef); err != nil {
+		return err
+	}
+	_, err := s.toNewRelic()
+	return err
+}
+
+// ValidateCreate checks the spec of a new NrqlAlertCondition
+func (s *NrqlAlertCondition) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed NrqlAlertCondition
+func (s *NrqlAlertCondition) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every NrqlAlertCondition to be deleted
+func (s *NrqlAlertCondition) ValidateDelete() error {
+	return nil
+}
+
+// ValidateCreate checks the spec of a new SecureCredential
+func (s *SecureCredential) ValidateCreate() error {
+	return s.validate()
+}
+
+// ValidateUpdate checks the spec of a changed SecureCredential
+func (s *SecureCredential) ValidateUpdate(old runtime.Object) error {
+	return validateUpdate(s, old, s.validate)
+}
+
+// ValidateDelete allows every SecureCredential to be deleted
+func (s *SecureCredential) ValidateDelete() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidate(t *testing.T) {
+	str := func(value string) *string { return &value }
+	frequency := func(value int64) *int64 { return &value }
+	meta := metav1.ObjectMeta{Name: "test", Namespace: "team"}
+	secretURL := ConfigurationValue{ValueFrom: &ConfigurationValueSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack"}, Key: "url"},
+	}}
+
+	tests := []struct {
+		name  string
+		obj   admission.Validator
+		valid bool
+	}{
+		{"simple monitor", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{URI: str("https://example.com")}}, true},
+		{"unknown monitor type", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str("PING")}}, false},
+		{"unsupported frequency", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Frequency: frequency(7)}}, false},
+		{"public location", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Locations: []*string{str("AWS_EU_WEST_1")}}}, true},
+		{"location only known to the account", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Locations: []*string{str("AWS_MARS_1")}}}, true},
+		{"empty location", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Locations: []*string{str("")}}}, false},
+		{"private location", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Locations: []*string{str("1234-office")}}}, true},
+		{"api monitor without script", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str(typeAPI)}}, false},
+		{"api monitor", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str(typeAPI), Script: &Script{ScriptText: str("$http.get('https://example.com')")}}}, true},
+		{"browser script without script", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str(typeScriptedBrowser)}}, false},
+		{"browser script from a config map", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str(typeScriptedBrowser), Script: &Script{ScriptFrom: &ScriptSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "scripts"}, Key: "login.js"},
+		}}}}, true},
+		{"inline script and script from", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Type: str(typeAPI), Script: &Script{ScriptText: str("1"), ScriptFrom: &ScriptSource{}}}}, false},
+		{"condition without policy", &Monitor{ObjectMeta: meta, Spec: MonitorSpec{Conditions: []Conditions{{}}}}, false},
+		{"incident preference", &AlertPolicy{ObjectMeta: meta, Spec: AlertPolicySpec{IncidentPreference: "PER_CONDITION"}}, true},
+		{"invalid incident preference", &AlertPolicy{ObjectMeta: meta, Spec: AlertPolicySpec{IncidentPreference: "PER_HOST"}}, false},
+		{"slack without url", &AlertChannel{ObjectMeta: meta, Spec: AlertChannelSpec{Slack: &SlackChannel{Channel: "#alerts"}}}, false},
+		{"slack url from a secret", &AlertChannel{ObjectMeta: meta, Spec: AlertChannelSpec{Slack: &SlackChannel{URL: secretURL}}}, true},
+		{"untyped slack without url", &AlertChannel{ObjectMeta: meta, Spec: AlertChannelSpec{Type: "slack", Configuration: data{"channel": {Value: "#alerts"}}}}, false},
+		{"nrql condition without query", &NrqlAlertCondition{ObjectMeta: meta, Spec: NrqlAlertConditionSpec{PolicyName: "policy"}}, false},
+		{"infra condition without policy", &InfraAlertCondition{ObjectMeta: meta, Spec: InfraAlertConditionSpec{Type: InfraHostNotReporting}}, false},
+		{"dashboard with two json sources", &Dashboard{ObjectMeta: meta, Spec: DashboardSpec{JSON: "{}", JSONFrom: &DashboardJSONSource{}}}, false},
+		{"secure credential", &SecureCredential{ObjectMeta: metav1.ObjectMeta{Name: "api-token"}, Spec: SecureCredentialSpec{ValueFrom: SecureCredentialSource{SecretKeyRef: secretURL.ValueFrom.SecretKeyRef}}}, true},
+		{"secure credential without secret", &SecureCredential{ObjectMeta: meta}, false},
+		{"secure credential with lower case key", &SecureCredential{ObjectMeta: meta, Spec: SecureCredentialSpec{Key: "token", ValueFrom: SecureCredentialSource{SecretKeyRef: secretURL.ValueFrom.SecretKeyRef}}}, false},
+		{"monitor set", &MonitorSet{ObjectMeta: meta, Spec: MonitorSetSpec{Generators: []MonitorSetGenerator{{List: []map[string]string{{"env": "prod"}}}}}}, true},
+		{"monitor set without generators", &MonitorSet{ObjectMeta: meta}, false},
+		{"monitor set generator with list and matrix", &MonitorSet{ObjectMeta: meta, Spec: MonitorSetSpec{Generators: []MonitorSetGenerator{{List: []map[string]string{}, Matrix: []MatrixGenerator{}}}}}, false},
+		{"monitor set with invalid selector", &MonitorSet{ObjectMeta: meta, Spec: MonitorSetSpec{Generators: []MonitorSetGenerator{{Services: &ServiceGenerator{Selector: metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}},
+		}}}}}}, false},
+		{"account without secret", &NewRelicAccount{ObjectMeta: meta}, false},
+	}
+
+	for _, test := range tests {
+		err := test.obj.ValidateCreate()
+		if test.valid && err != nil {
+			t.Errorf("%s: unexpected error %s", test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%s: expected the spec to be rejected", test.name)
+		}
+		if update := test.obj.ValidateUpdate(nil); (update == nil) != (err == nil) {
+			t.Errorf("%s: expected update to validate like create", test.name)
+		}
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	frequency := int64(7)
+	valid := &Monitor{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "team"}}
+	invalid := valid.DeepCopy()
+	invalid.Spec.Frequency = &frequency
+
+	if err := invalid.ValidateUpdate(valid); err == nil {
+		t.Fatal("expected a change to an invalid spec to be rejected")
+	}
+
+	// stored before the check existed, the operator still writes its status and finalizers
+	if err := invalid.ValidateUpdate(invalid.DeepCopy()); err != nil {
+		t.Fatalf("expected an unchanged spec to be accepted: %s", err)
+	}
+
+	deleted := invalid.DeepCopy()
+	now := metav1.Now()
+	deleted.DeletionTimestamp = &now
+	if err := deleted.ValidateUpdate(valid); err != nil {
+		t.Fatalf("expected an update of a deleted object to be accepted: %s", err)
+	}
+}
@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -76,152 +77,1583 @@ func (in *AlertChannelSpec) DeepCopyInto(out *AlertChannelSpec) {
 		in, out := &in.Configuration, &out.Configuration
 		*out = make(data, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PagerDuty != nil {
+		in, out := &in.PagerDuty, &out.PagerDuty
+		*out = new(PagerDutyChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpsGenie != nil {
+		in, out := &in.OpsGenie, &out.OpsGenie
+		*out = new(OpsGenieChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VictorOps != nil {
+		in, out := &in.VictorOps, &out.VictorOps
+		*out = new(VictorOpsChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookChannel)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(UserChannel)
+		**out = **in
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyRefs != nil {
+		in, out := &in.PolicyRefs, &out.PolicyRefs
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelSpec.
+func (in *AlertChannelSpec) DeepCopy() *AlertChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertChannelStatus) DeepCopyInto(out *AlertChannelStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
 	if in.Policies != nil {
 		in, out := &in.Policies, &out.Policies
+		*out = make([]PolicyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigurationHash != nil {
+		in, out := &in.ConfigurationHash, &out.ConfigurationHash
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelStatus.
+func (in *AlertChannelStatus) DeepCopy() *AlertChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertCondition) DeepCopyInto(out *AlertCondition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertCondition.
+func (in *AlertCondition) DeepCopy() *AlertCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertCondition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertConditionList) DeepCopyInto(out *AlertConditionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AlertCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConditionList.
+func (in *AlertConditionList) DeepCopy() *AlertConditionList {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertConditionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertConditionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertConditionSpec) DeepCopyInto(out *AlertConditionSpec) {
+	*out = *in
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserDefined != nil {
+		in, out := &in.UserDefined, &out.UserDefined
+		*out = new(AlertConditionUserDefined)
+		**out = **in
+	}
+	out.Critical = in.Critical
+	if in.Warning != nil {
+		in, out := &in.Warning, &out.Warning
+		*out = new(AlertThreshold)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConditionSpec.
+func (in *AlertConditionSpec) DeepCopy() *AlertConditionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertConditionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertConditionUserDefined) DeepCopyInto(out *AlertConditionUserDefined) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertConditionUserDefined.
+func (in *AlertConditionUserDefined) DeepCopy() *AlertConditionUserDefined {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertConditionUserDefined)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicy) DeepCopyInto(out *AlertPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicy.
+func (in *AlertPolicy) DeepCopy() *AlertPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicyList) DeepCopyInto(out *AlertPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AlertPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyList.
+func (in *AlertPolicyList) DeepCopy() *AlertPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertPolicySpec) DeepCopyInto(out *AlertPolicySpec) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChannelRefs != nil {
+		in, out := &in.ChannelRefs, &out.ChannelRefs
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicySpec.
+func (in *AlertPolicySpec) DeepCopy() *AlertPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertThreshold) DeepCopyInto(out *AlertThreshold) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertThreshold.
+func (in *AlertThreshold) DeepCopy() *AlertThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conditions) DeepCopyInto(out *Conditions) {
+	*out = *in
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.RunbookURL != nil {
+		in, out := &in.RunbookURL, &out.RunbookURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
+func (in *Conditions) DeepCopy() *Conditions {
+	if in == nil {
+		return nil
+	}
+	out := new(Conditions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationValue) DeepCopyInto(out *ConfigurationValue) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ConfigurationValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationValue.
+func (in *ConfigurationValue) DeepCopy() *ConfigurationValue {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationValueSource) DeepCopyInto(out *ConfigurationValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationValueSource.
+func (in *ConfigurationValueSource) DeepCopy() *ConfigurationValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dashboard) DeepCopyInto(out *Dashboard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dashboard.
+func (in *Dashboard) DeepCopy() *Dashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(Dashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Dashboard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardFilter) DeepCopyInto(out *DashboardFilter) {
+	*out = *in
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardFilter.
+func (in *DashboardFilter) DeepCopy() *DashboardFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardJSONSource) DeepCopyInto(out *DashboardJSONSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardJSONSource.
+func (in *DashboardJSONSource) DeepCopy() *DashboardJSONSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardJSONSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardList) DeepCopyInto(out *DashboardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Dashboard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardList.
+func (in *DashboardList) DeepCopy() *DashboardList {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DashboardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+	*out = *in
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(DashboardFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Widgets != nil {
+		in, out := &in.Widgets, &out.Widgets
+		*out = make([]DashboardWidget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JSONFrom != nil {
+		in, out := &in.JSONFrom, &out.JSONFrom
+		*out = new(DashboardJSONSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
+func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidget) DeepCopyInto(out *DashboardWidget) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(DashboardWidgetThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Layout = in.Layout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidget.
+func (in *DashboardWidget) DeepCopy() *DashboardWidget {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetLayout) DeepCopyInto(out *DashboardWidgetLayout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetLayout.
+func (in *DashboardWidgetLayout) DeepCopy() *DashboardWidgetLayout {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetLayout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardWidgetThreshold) DeepCopyInto(out *DashboardWidgetThreshold) {
+	*out = *in
+	if in.Red != nil {
+		in, out := &in.Red, &out.Red
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Yellow != nil {
+		in, out := &in.Yellow, &out.Yellow
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardWidgetThreshold.
+func (in *DashboardWidgetThreshold) DeepCopy() *DashboardWidgetThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardWidgetThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Data) DeepCopyInto(out *Data) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Data.
+func (in *Data) DeepCopy() *Data {
+	if in == nil {
+		return nil
+	}
+	out := new(Data)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailChannel) DeepCopyInto(out *EmailChannel) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailChannel.
+func (in *EmailChannel) DeepCopy() *EmailChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraAlertCondition) DeepCopyInto(out *InfraAlertCondition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraAlertCondition.
+func (in *InfraAlertCondition) DeepCopy() *InfraAlertCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraAlertCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InfraAlertCondition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraAlertConditionList) DeepCopyInto(out *InfraAlertConditionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InfraAlertCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraAlertConditionList.
+func (in *InfraAlertConditionList) DeepCopy() *InfraAlertConditionList {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraAlertConditionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InfraAlertConditionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraAlertConditionSpec) DeepCopyInto(out *InfraAlertConditionSpec) {
+	*out = *in
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Critical = in.Critical
+	if in.Warning != nil {
+		in, out := &in.Warning, &out.Warning
+		*out = new(InfraAlertThreshold)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraAlertConditionSpec.
+func (in *InfraAlertConditionSpec) DeepCopy() *InfraAlertConditionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraAlertConditionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraAlertThreshold) DeepCopyInto(out *InfraAlertThreshold) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraAlertThreshold.
+func (in *InfraAlertThreshold) DeepCopy() *InfraAlertThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraAlertThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixGenerator) DeepCopyInto(out *MatrixGenerator) {
+	*out = *in
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ServiceGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixGenerator.
+func (in *MatrixGenerator) DeepCopy() *MatrixGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitor) DeepCopyInto(out *Monitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitor.
+func (in *Monitor) DeepCopy() *Monitor {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Monitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorList) DeepCopyInto(out *MonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Monitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorList.
+func (in *MonitorList) DeepCopy() *MonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorOptions) DeepCopyInto(out *MonitorOptions) {
+	*out = *in
+	if in.ValidationString != nil {
+		in, out := &in.ValidationString, &out.ValidationString
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorOptions.
+func (in *MonitorOptions) DeepCopy() *MonitorOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSet) DeepCopyInto(out *MonitorSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSet.
+func (in *MonitorSet) DeepCopy() *MonitorSet {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonitorSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSetGenerator) DeepCopyInto(out *MonitorSetGenerator) {
+	*out = *in
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ServiceGenerator)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make([]MatrixGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSetGenerator.
+func (in *MonitorSetGenerator) DeepCopy() *MonitorSetGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSetGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSetList) DeepCopyInto(out *MonitorSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MonitorSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSetList.
+func (in *MonitorSetList) DeepCopy() *MonitorSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonitorSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSetSpec) DeepCopyInto(out *MonitorSetSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]MonitorSetGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSetSpec.
+func (in *MonitorSetSpec) DeepCopy() *MonitorSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSetStatus) DeepCopyInto(out *MonitorSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSetStatus.
+func (in *MonitorSetStatus) DeepCopy() *MonitorSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorSpec) DeepCopyInto(out *MonitorSpec) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.Frequency != nil {
+		in, out := &in.Frequency, &out.Frequency
+		*out = new(int64)
+		**out = **in
+	}
+	if in.URI != nil {
+		in, out := &in.URI, &out.URI
+		*out = new(string)
+		**out = **in
+	}
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(MonitorStatusString)
+		**out = **in
+	}
+	if in.SLAThreshold != nil {
+		in, out := &in.SLAThreshold, &out.SLAThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ManageUpdates != nil {
+		in, out := &in.ManageUpdates, &out.ManageUpdates
+		*out = new(bool)
+		**out = **in
+	}
+	in.Options.DeepCopyInto(&out.Options)
+	if in.Script != nil {
+		in, out := &in.Script, &out.Script
+		*out = new(Script)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSpec.
+func (in *MonitorSpec) DeepCopy() *MonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorStatus) DeepCopyInto(out *MonitorStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.SyntheticsConditions != nil {
+		in, out := &in.SyntheticsConditions, &out.SyntheticsConditions
+		*out = make([]SyntheticsConditionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScriptHash != nil {
+		in, out := &in.ScriptHash, &out.ScriptHash
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorStatus.
+func (in *MonitorStatus) DeepCopy() *MonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorTemplate) DeepCopyInto(out *MonitorTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorTemplate.
+func (in *MonitorTemplate) DeepCopy() *MonitorTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccount) DeepCopyInto(out *NewRelicAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccount.
+func (in *NewRelicAccount) DeepCopy() *NewRelicAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NewRelicAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountList) DeepCopyInto(out *NewRelicAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NewRelicAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountList.
+func (in *NewRelicAccountList) DeepCopy() *NewRelicAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NewRelicAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountSpec) DeepCopyInto(out *NewRelicAccountSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountSpec.
+func (in *NewRelicAccountSpec) DeepCopy() *NewRelicAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NrqlAlertCondition) DeepCopyInto(out *NrqlAlertCondition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NrqlAlertCondition.
+func (in *NrqlAlertCondition) DeepCopy() *NrqlAlertCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NrqlAlertCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NrqlAlertCondition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NrqlAlertConditionList) DeepCopyInto(out *NrqlAlertConditionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NrqlAlertCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NrqlAlertConditionList.
+func (in *NrqlAlertConditionList) DeepCopy() *NrqlAlertConditionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NrqlAlertConditionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NrqlAlertConditionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NrqlAlertConditionSpec) DeepCopyInto(out *NrqlAlertConditionSpec) {
+	*out = *in
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+	out.Critical = in.Critical
+	if in.Warning != nil {
+		in, out := &in.Warning, &out.Warning
+		*out = new(AlertThreshold)
+		**out = **in
+	}
+	if in.Signal != nil {
+		in, out := &in.Signal, &out.Signal
+		*out = new(NrqlAlertSignal)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImportID != nil {
+		in, out := &in.ImportID, &out.ImportID
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NrqlAlertConditionSpec.
+func (in *NrqlAlertConditionSpec) DeepCopy() *NrqlAlertConditionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NrqlAlertConditionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NrqlAlertSignal) DeepCopyInto(out *NrqlAlertSignal) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NrqlAlertSignal.
+func (in *NrqlAlertSignal) DeepCopy() *NrqlAlertSignal {
+	if in == nil {
+		return nil
+	}
+	out := new(NrqlAlertSignal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsGenieChannel) DeepCopyInto(out *OpsGenieChannel) {
+	*out = *in
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertChannelSpec.
-func (in *AlertChannelSpec) DeepCopy() *AlertChannelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsGenieChannel.
+func (in *OpsGenieChannel) DeepCopy() *OpsGenieChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertChannelSpec)
+	out := new(OpsGenieChannel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertPolicy) DeepCopyInto(out *AlertPolicy) {
+func (in *PagerDutyChannel) DeepCopyInto(out *PagerDutyChannel) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ServiceKey.DeepCopyInto(&out.ServiceKey)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicy.
-func (in *AlertPolicy) DeepCopy() *AlertPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyChannel.
+func (in *PagerDutyChannel) DeepCopy() *PagerDutyChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertPolicy)
+	out := new(PagerDutyChannel)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AlertPolicy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertPolicyList) DeepCopyInto(out *AlertPolicyList) {
+func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AlertPolicy, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicyList.
-func (in *AlertPolicyList) DeepCopy() *AlertPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyStatus.
+func (in *PolicyStatus) DeepCopy() *PolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertPolicyList)
+	out := new(PolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AlertPolicyList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AlertPolicySpec) DeepCopyInto(out *AlertPolicySpec) {
+func (in *Script) DeepCopyInto(out *Script) {
 	*out = *in
-	if in.Channels != nil {
-		in, out := &in.Channels, &out.Channels
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ScriptText != nil {
+		in, out := &in.ScriptText, &out.ScriptText
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScriptFrom != nil {
+		in, out := &in.ScriptFrom, &out.ScriptFrom
+		*out = new(ScriptSource)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertPolicySpec.
-func (in *AlertPolicySpec) DeepCopy() *AlertPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Script.
+func (in *Script) DeepCopy() *Script {
 	if in == nil {
 		return nil
 	}
-	out := new(AlertPolicySpec)
+	out := new(Script)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Conditions) DeepCopyInto(out *Conditions) {
+func (in *ScriptSource) DeepCopyInto(out *ScriptSource) {
 	*out = *in
-	if in.RunbookURL != nil {
-		in, out := &in.RunbookURL, &out.RunbookURL
-		*out = new(string)
-		**out = **in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conditions.
-func (in *Conditions) DeepCopy() *Conditions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptSource.
+func (in *ScriptSource) DeepCopy() *ScriptSource {
 	if in == nil {
 		return nil
 	}
-	out := new(Conditions)
+	out := new(ScriptSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Dashboard) DeepCopyInto(out *Dashboard) {
+func (in *SecureCredential) DeepCopyInto(out *SecureCredential) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dashboard.
-func (in *Dashboard) DeepCopy() *Dashboard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureCredential.
+func (in *SecureCredential) DeepCopy() *SecureCredential {
 	if in == nil {
 		return nil
 	}
-	out := new(Dashboard)
+	out := new(SecureCredential)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Dashboard) DeepCopyObject() runtime.Object {
+func (in *SecureCredential) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -229,13 +1661,13 @@ func (in *Dashboard) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardList) DeepCopyInto(out *DashboardList) {
+func (in *SecureCredentialList) DeepCopyInto(out *SecureCredentialList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Dashboard, len(*in))
+		*out = make([]SecureCredential, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -243,18 +1675,18 @@ func (in *DashboardList) DeepCopyInto(out *DashboardList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardList.
-func (in *DashboardList) DeepCopy() *DashboardList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureCredentialList.
+func (in *SecureCredentialList) DeepCopy() *SecureCredentialList {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardList)
+	out := new(SecureCredentialList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DashboardList) DeepCopyObject() runtime.Object {
+func (in *SecureCredentialList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -262,176 +1694,140 @@ func (in *DashboardList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
+func (in *SecureCredentialSource) DeepCopyInto(out *SecureCredentialSource) {
 	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSpec.
-func (in *DashboardSpec) DeepCopy() *DashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureCredentialSource.
+func (in *SecureCredentialSource) DeepCopy() *SecureCredentialSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DashboardSpec)
+	out := new(SecureCredentialSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Data) DeepCopyInto(out *Data) {
+func (in *SecureCredentialSpec) DeepCopyInto(out *SecureCredentialSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Data.
-func (in *Data) DeepCopy() *Data {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureCredentialSpec.
+func (in *SecureCredentialSpec) DeepCopy() *SecureCredentialSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Data)
+	out := new(SecureCredentialSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Monitor) DeepCopyInto(out *Monitor) {
+func (in *SecureCredentialStatus) DeepCopyInto(out *SecureCredentialStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
+	if in.ConfigurationHash != nil {
+		in, out := &in.ConfigurationHash, &out.ConfigurationHash
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitor.
-func (in *Monitor) DeepCopy() *Monitor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecureCredentialStatus.
+func (in *SecureCredentialStatus) DeepCopy() *SecureCredentialStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Monitor)
+	out := new(SecureCredentialStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Monitor) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitorList) DeepCopyInto(out *MonitorList) {
+func (in *ServiceGenerator) DeepCopyInto(out *ServiceGenerator) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Monitor, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Selector.DeepCopyInto(&out.Selector)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorList.
-func (in *MonitorList) DeepCopy() *MonitorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceGenerator.
+func (in *ServiceGenerator) DeepCopy() *ServiceGenerator {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitorList)
+	out := new(ServiceGenerator)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MonitorList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackChannel) DeepCopyInto(out *SlackChannel) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackChannel.
+func (in *SlackChannel) DeepCopy() *SlackChannel {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(SlackChannel)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitorOptions) DeepCopyInto(out *MonitorOptions) {
+func (in *Spec) DeepCopyInto(out *Spec) {
 	*out = *in
-	if in.ValidationString != nil {
-		in, out := &in.ValidationString, &out.ValidationString
-		*out = new(string)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorOptions.
-func (in *MonitorOptions) DeepCopy() *MonitorOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spec.
+func (in *Spec) DeepCopy() *Spec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitorOptions)
+	out := new(Spec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitorSpec) DeepCopyInto(out *MonitorSpec) {
+func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(string)
-		**out = **in
-	}
-	if in.Frequency != nil {
-		in, out := &in.Frequency, &out.Frequency
-		*out = new(int64)
-		**out = **in
-	}
-	if in.URI != nil {
-		in, out := &in.URI, &out.URI
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
 		*out = new(string)
 		**out = **in
 	}
-	if in.Locations != nil {
-		in, out := &in.Locations, &out.Locations
-		*out = make([]*string, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(string)
-				**out = **in
-			}
-		}
-	}
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(MonitorStatusString)
-		**out = **in
-	}
-	if in.SLAThreshold != nil {
-		in, out := &in.SLAThreshold, &out.SLAThreshold
-		*out = new(float64)
-		**out = **in
-	}
-	if in.ManageUpdates != nil {
-		in, out := &in.ManageUpdates, &out.ManageUpdates
-		*out = new(bool)
-		**out = **in
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
 	}
-	in.Options.DeepCopyInto(&out.Options)
-	if in.Script != nil {
-		in, out := &in.Script, &out.Script
-		*out = new(Script)
-		(*in).DeepCopyInto(*out)
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Conditions, len(*in))
+		*out = make([]Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -439,75 +1835,96 @@ func (in *MonitorSpec) DeepCopyInto(out *MonitorSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorSpec.
-func (in *MonitorSpec) DeepCopy() *MonitorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
+func (in *Status) DeepCopy() *Status {
 	if in == nil {
 		return nil
 	}
-	out := new(MonitorSpec)
+	out := new(Status)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Script) DeepCopyInto(out *Script) {
+func (in *SyntheticsConditionStatus) DeepCopyInto(out *SyntheticsConditionStatus) {
 	*out = *in
-	if in.ScriptText != nil {
-		in, out := &in.ScriptText, &out.ScriptText
-		*out = new(string)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyntheticsConditionStatus.
+func (in *SyntheticsConditionStatus) DeepCopy() *SyntheticsConditionStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(SyntheticsConditionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserChannel) DeepCopyInto(out *UserChannel) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Script.
-func (in *Script) DeepCopy() *Script {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserChannel.
+func (in *UserChannel) DeepCopy() *UserChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(Script)
+	out := new(UserChannel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Spec) DeepCopyInto(out *Spec) {
+func (in *VictorOpsChannel) DeepCopyInto(out *VictorOpsChannel) {
 	*out = *in
+	in.Key.DeepCopyInto(&out.Key)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spec.
-func (in *Spec) DeepCopy() *Spec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VictorOpsChannel.
+func (in *VictorOpsChannel) DeepCopy() *VictorOpsChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(Spec)
+	out := new(VictorOpsChannel)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Status) DeepCopyInto(out *Status) {
+func (in *WebhookChannel) DeepCopyInto(out *WebhookChannel) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
+	if in.AuthPassword != nil {
+		in, out := &in.AuthPassword, &out.AuthPassword
+		*out = new(ConfigurationValue)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Hash != nil {
-		in, out := &in.Hash, &out.Hash
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]ConfigurationValue, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
-func (in *Status) DeepCopy() *Status {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookChannel.
+func (in *WebhookChannel) DeepCopy() *WebhookChannel {
 	if in == nil {
 		return nil
 	}
-	out := new(Status)
+	out := new(WebhookChannel)
 	in.DeepCopyInto(out)
 	return out
 }
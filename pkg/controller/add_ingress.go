@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/sstarcher/newrelic-operator/pkg/account"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/ingress"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	// Generated monitors are synced by the monitor controller, no account is needed here
+	AddToManagerFuncs = append(AddToManagerFuncs, func(mgr manager.Manager, _ *account.Resolver) error {
+		return ingress.Add(mgr)
+	})
+}
@@ -3,14 +3,18 @@ package alertchannel
 import (
 	"context"
 
+	"github.com/sstarcher/newrelic-operator/pkg/account"
 	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/dependents"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -19,13 +23,18 @@ var log = logf.Log.WithName("controller_alertchannel")
 
 // Add creates a new AlertChannel Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+func Add(mgr manager.Manager, accounts *account.Resolver) error {
+	return add(mgr, newReconciler(mgr, accounts))
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileAlertChannel{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager, accounts *account.Resolver) reconcile.Reconciler {
+	return &ReconcileAlertChannel{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		accounts: accounts,
+		recorder: mgr.GetEventRecorderFor("alertchannel-controller"),
+	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -37,7 +46,28 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource AlertChannel
-	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.AlertChannel{}}, &handler.EnqueueRequestForObject{})
+	// Status is written by the controller itself, only react to spec changes and deletion
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.AlertChannel{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the Secrets holding channel configuration so rotated values are applied
+	err = dependents.WatchSecrets(c, mgr.GetClient(), &newrelicv1alpha1.AlertChannelList{})
+	if err != nil {
+		return err
+	}
+
+	// Requeue channels waiting on an AlertPolicy resource they refer to
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.AlertPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: dependents.Of(mgr.GetClient(), &newrelicv1alpha1.AlertChannelList{}, dependents.UsesAlertPolicy),
+	}, dependents.IDChanged)
+	if err != nil {
+		return err
+	}
+
+	// Requeue alert channels when the NewRelicAccount they use or its Secret changes
+	err = dependents.WatchAccounts(c, mgr.GetClient(), &newrelicv1alpha1.AlertChannelList{})
 	if err != nil {
 		return err
 	}
@@ -54,6 +84,9 @@ type ReconcileAlertChannel struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// accounts finds the New Relic client for each object
+	accounts *account.Resolver
+	recorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a AlertChannel object and makes changes based on the state read
@@ -78,6 +111,14 @@ func (r *ReconcileAlertChannel) Reconcile(request reconcile.Request) (reconcile.
 		return reconcile.Result{}, err
 	}
 
-	reconcileResult := newrelicv1alpha1.DoReconcile(reqLogger, instance)
-	return reconcileResult, r.client.Update(context.TODO(), instance)
+	original := instance.DeepCopy()
+	nrClient, err := r.accounts.ClientFor(context.TODO(), instance)
+	if instance.Status.HandleOnErrorMessage(newrelicv1alpha1.WithLogger(context.TODO(), &reqLogger), err, "unable to resolve New Relic account") {
+		return newrelicv1alpha1.DefaultRequeue, newrelicv1alpha1.Persist(context.TODO(), r.client, original, instance)
+	}
+
+	ctx := newrelicv1alpha1.WithRecorder(newrelicv1alpha1.WithClient(context.TODO(), nrClient), r.recorder, instance)
+	ctx = newrelicv1alpha1.WithAccounts(newrelicv1alpha1.WithKubeClient(ctx, r.client), r.accounts)
+	reconcileResult := newrelicv1alpha1.DoReconcile(ctx, reqLogger, instance)
+	return reconcileResult, newrelicv1alpha1.Persist(context.TODO(), r.client, original, instance)
 }
@@ -0,0 +1,155 @@
+// Package alertcondition reconciles the APM, infrastructure and NRQL alert conditions, they only differ in their kind
+package alertcondition
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/sstarcher/newrelic-operator/pkg/account"
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/dependents"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// conditionKind is a condition resource with a controller of its own
+type conditionKind struct {
+	// name of the controller, the logger and the event source
+	name string
+	// object returns an empty resource of the kind
+	object func() newrelicv1alpha1.CRD
+	list   runtime.Object
+}
+
+var kinds = []conditionKind{
+	{
+		name:   "alertcondition",
+		object: func() newrelicv1alpha1.CRD { return &newrelicv1alpha1.AlertCondition{} },
+		list:   &newrelicv1alpha1.AlertConditionList{},
+	},
+	{
+		name:   "infraalertcondition",
+		object: func() newrelicv1alpha1.CRD { return &newrelicv1alpha1.InfraAlertCondition{} },
+		list:   &newrelicv1alpha1.InfraAlertConditionList{},
+	},
+	{
+		name:   "nrqlalertcondition",
+		object: func() newrelicv1alpha1.CRD { return &newrelicv1alpha1.NrqlAlertCondition{} },
+		list:   &newrelicv1alpha1.NrqlAlertConditionList{},
+	},
+}
+
+// Add creates a Controller for each kind of alert condition and adds them to the Manager. The Manager will set fields
+// on the Controllers and Start them when the Manager is Started.
+func Add(mgr manager.Manager, accounts *account.Resolver) error {
+	for _, kind := range kinds {
+		if err := add(mgr, newReconciler(mgr, accounts, kind), kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, accounts *account.Resolver, kind conditionKind) reconcile.Reconciler {
+	return &ReconcileAlertCondition{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		accounts: accounts,
+		recorder: mgr.GetEventRecorderFor(kind.name + "-controller"),
+		log:      logf.Log.WithName("controller_" + kind.name),
+		object:   kind.object,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler, kind conditionKind) error {
+	// Create a new controller
+	c, err := controller.New(kind.name+"-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the primary resource
+	// Status is written by the controller itself, only react to spec changes and deletion
+	err = c.Watch(&source.Kind{Type: kind.object()}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Requeue conditions waiting on an AlertPolicy resource they refer to
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.AlertPolicy{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: dependents.Of(mgr.GetClient(), kind.list, dependents.UsesAlertPolicy),
+	}, dependents.IDChanged)
+	if err != nil {
+		return err
+	}
+
+	// Requeue conditions when the NewRelicAccount they use or its Secret changes
+	err = dependents.WatchAccounts(c, mgr.GetClient(), kind.list)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileAlertCondition implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAlertCondition{}
+
+// ReconcileAlertCondition reconciles one kind of alert condition
+type ReconcileAlertCondition struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+	// accounts finds the New Relic client for each object
+	accounts *account.Resolver
+	recorder record.EventRecorder
+	log      logr.Logger
+	// object returns an empty resource of the reconciled kind
+	object func() newrelicv1alpha1.CRD
+}
+
+// Reconcile reads that state of the cluster for an alert condition and makes changes based on the state read
+// and what is in its Spec
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileAlertCondition) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the condition instance
+	instance := r.object()
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	original := instance.DeepCopyObject().(newrelicv1alpha1.CRD)
+	nrClient, err := r.accounts.ClientFor(context.TODO(), instance)
+	if instance.GetStatus().HandleOnErrorMessage(newrelicv1alpha1.WithLogger(context.TODO(), &reqLogger), err, "unable to resolve New Relic account") {
+		return newrelicv1alpha1.DefaultRequeue, newrelicv1alpha1.Persist(context.TODO(), r.client, original, instance)
+	}
+
+	ctx := newrelicv1alpha1.WithRecorder(newrelicv1alpha1.WithClient(context.TODO(), nrClient), r.recorder, instance)
+	ctx = newrelicv1alpha1.WithAccounts(newrelicv1alpha1.WithKubeClient(ctx, r.client), r.accounts)
+	reconcileResult := newrelicv1alpha1.DoReconcile(ctx, reqLogger, instance)
+	return reconcileResult, newrelicv1alpha1.Persist(context.TODO(), r.client, original, instance)
+}
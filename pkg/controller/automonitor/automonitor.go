@@ -0,0 +1,214 @@
+// Package automonitor generates Monitor resources for annotated Ingresses and Services, and keeps the monitors owned by a resource in sync
+package automonitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("automonitor")
+
+// Annotations read from Ingresses and Services
+const (
+	// AnnotationMonitor set to "true" generates a Monitor for each URL of the resource
+	AnnotationMonitor = "newrelic.shanestarcher.com/monitor"
+	// AnnotationFrequency sets the frequency in minutes
+	AnnotationFrequency = "newrelic.shanestarcher.com/monitor-frequency"
+	// AnnotationLocations sets a comma separated list of locations
+	AnnotationLocations = "newrelic.shanestarcher.com/monitor-locations"
+	// AnnotationValidationString sets the text the response has to contain
+	AnnotationValidationString = "newrelic.shanestarcher.com/monitor-validation-string"
+	// AnnotationPolicy alerts in the AlertPolicy resource of the same namespace
+	AnnotationPolicy = "newrelic.shanestarcher.com/monitor-policy"
+	// AnnotationPolicyName alerts in the New Relic policy with this name
+	AnnotationPolicyName = "newrelic.shanestarcher.com/monitor-policy-name"
+	// AnnotationAccount sets the NewRelicAccount of the monitors
+	AnnotationAccount = "newrelic.shanestarcher.com/monitor-account"
+	// AnnotationPath sets the path monitored on a Service, it defaults to /
+	AnnotationPath = "newrelic.shanestarcher.com/monitor-path"
+)
+
+// Labels set on generated monitors to find them again, the owner is identified by its UID as names can be longer
+// than a label value
+const (
+	LabelOwnerKind = "newrelic.shanestarcher.com/owner-kind"
+	LabelOwnerUID  = "newrelic.shanestarcher.com/owner-uid"
+)
+
+// Target is a URL to monitor
+type Target struct {
+	// Suffix is appended to the name of the owner to name the Monitor
+	Suffix string
+	URI    string
+}
+
+// Owner is the Ingress, Service or MonitorSet monitors are generated for
+type Owner interface {
+	metav1.Object
+	runtime.Object
+}
+
+// Apply generates the monitors of owner for targets, or deletes them when owner is no longer annotated,
+// problems are also reported as events of owner
+func Apply(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, kind string, owner Owner, targets []Target) error {
+	if !enabled(owner) || owner.GetDeletionTimestamp() != nil {
+		return prune(ctx, c, kind, owner, nil)
+	}
+
+	spec, err := monitorSpec(owner.GetAnnotations())
+	if err != nil {
+		// the annotations have to be fixed, retrying would not help
+		recorder.Event(owner, corev1.EventTypeWarning, "InvalidAnnotation", err.Error())
+		return nil
+	}
+
+	err = sync(ctx, c, scheme, kind, owner, spec, targets)
+	if err != nil {
+		recorder.Event(owner, corev1.EventTypeWarning, "MonitorFailed", err.Error())
+	}
+	return err
+}
+
+// enabled reports if the resource asks for monitors
+func enabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[AnnotationMonitor] == "true"
+}
+
+// monitorSpec returns the spec shared by the monitors of a resource, without the URI
+func monitorSpec(annotations map[string]string) (*newrelicv1alpha1.MonitorSpec, error) {
+	spec := &newrelicv1alpha1.MonitorSpec{}
+
+	if value, ok := annotations[AnnotationFrequency]; ok {
+		frequency, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number of minutes: %v", AnnotationFrequency, err)
+		}
+		spec.Frequency = &frequency
+	}
+
+	if value, ok := annotations[AnnotationLocations]; ok {
+		for _, item := range strings.Split(value, ",") {
+			location := strings.TrimSpace(item)
+			if location != "" {
+				spec.Locations = append(spec.Locations, &location)
+			}
+		}
+	}
+
+	if value, ok := annotations[AnnotationValidationString]; ok {
+		spec.Options.ValidationString = &value
+	}
+
+	if value, ok := annotations[AnnotationPolicy]; ok {
+		spec.Conditions = append(spec.Conditions, newrelicv1alpha1.Conditions{
+			PolicyRef: &newrelicv1alpha1.ObjectReference{Name: value},
+		})
+	}
+	if value, ok := annotations[AnnotationPolicyName]; ok {
+		spec.Conditions = append(spec.Conditions, newrelicv1alpha1.Conditions{PolicyName: value})
+	}
+
+	if value, ok := annotations[AnnotationAccount]; ok {
+		spec.AccountRef = &corev1.LocalObjectReference{Name: value}
+	}
+
+	return spec, nil
+}
+
+// sync generates a Monitor with spec for each target
+func sync(ctx context.Context, c client.Client, scheme *runtime.Scheme, kind string, owner Owner, spec *newrelicv1alpha1.MonitorSpec, targets []Target) error {
+	monitors := []newrelicv1alpha1.Monitor{}
+	for _, target := range targets {
+		uri := target.URI
+		monitor := newrelicv1alpha1.Monitor{
+			ObjectMeta: metav1.ObjectMeta{Name: newrelicv1alpha1.MonitorName(owner.GetName(), target.Suffix)},
+			Spec:       *spec.DeepCopy(),
+		}
+		monitor.Spec.URI = &uri
+		monitors = append(monitors, monitor)
+	}
+	return SyncMonitors(ctx, c, scheme, kind, owner, monitors)
+}
+
+// SyncMonitors creates or updates the monitors controlled by owner and deletes the monitors of owner that are not
+// listed, the name, labels and spec of the listed monitors are applied
+func SyncMonitors(ctx context.Context, c client.Client, scheme *runtime.Scheme, kind string, owner Owner, monitors []newrelicv1alpha1.Monitor) error {
+	keep := map[string]bool{}
+	for i := range monitors {
+		desired := &monitors[i]
+		monitor := &newrelicv1alpha1.Monitor{ObjectMeta: metav1.ObjectMeta{
+			Name:      desired.Name,
+			Namespace: owner.GetNamespace(),
+		}}
+		keep[monitor.Name] = true
+
+		// never take over a Monitor created by hand
+		existing := &newrelicv1alpha1.Monitor{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: monitor.Namespace, Name: monitor.Name}, existing)
+		if err == nil && !metav1.IsControlledBy(existing, owner) {
+			return fmt.Errorf("monitor %s already exists and was not generated for %s %s", monitor.Name, kind, owner.GetName())
+		}
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		result, err := controllerutil.CreateOrUpdate(ctx, c, monitor, func() error {
+			monitor.Spec = *desired.Spec.DeepCopy()
+			// the CRD defaults the stored spec, defaulting here too keeps updates from flapping
+			monitor.Default()
+
+			if monitor.Labels == nil {
+				monitor.Labels = map[string]string{}
+			}
+			for key, value := range desired.Labels {
+				monitor.Labels[key] = value
+			}
+			monitor.Labels[LabelOwnerKind] = kind
+			monitor.Labels[LabelOwnerUID] = string(owner.GetUID())
+			return controllerutil.SetControllerReference(owner, monitor, scheme)
+		})
+		if err != nil {
+			return err
+		}
+		if result != controllerutil.OperationResultNone {
+			log.Info("monitor "+string(result), "Namespace", monitor.Namespace, "Name", monitor.Name)
+		}
+	}
+
+	return prune(ctx, c, kind, owner, keep)
+}
+
+// prune deletes the monitors generated for owner except the ones to keep, they are told apart by their controller
+// reference so monitors labelled by an older version are found as well
+func prune(ctx context.Context, c client.Client, kind string, owner metav1.Object, keep map[string]bool) error {
+	list := &newrelicv1alpha1.MonitorList{}
+	err := c.List(ctx, list, client.InNamespace(owner.GetNamespace()), client.MatchingLabels{LabelOwnerKind: kind})
+	if err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		monitor := &list.Items[i]
+		if keep[monitor.Name] || !metav1.IsControlledBy(monitor, owner) {
+			continue
+		}
+
+		log.Info("monitor deleted", "Namespace", monitor.Namespace, "Name", monitor.Name)
+		if err := c.Delete(ctx, monitor); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
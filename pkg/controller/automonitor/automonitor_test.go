@@ -0,0 +1,164 @@
+package automonitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func monitors(t *testing.T, c client.Client) map[string]newrelicv1alpha1.Monitor {
+	list := &newrelicv1alpha1.MonitorList{}
+	if err := c.List(context.TODO(), list, client.InNamespace("team")); err != nil {
+		t.Fatal(err)
+	}
+
+	items := map[string]newrelicv1alpha1.Monitor{}
+	for _, item := range list.Items {
+		items[item.Name] = item
+	}
+	return items
+}
+
+func TestApply(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := networkingv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	manual := &newrelicv1alpha1.Monitor{ObjectMeta: metav1.ObjectMeta{Name: "web-manual", Namespace: "team"}}
+	c := fake.NewFakeClientWithScheme(s, manual)
+	recorder := record.NewFakeRecorder(10)
+	ctx := context.TODO()
+
+	ingress := &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:      "web",
+		Namespace: "team",
+		UID:       types.UID("web-uid"),
+		Annotations: map[string]string{
+			AnnotationMonitor:          "true",
+			AnnotationFrequency:        "5",
+			AnnotationLocations:        "AWS_US_EAST_1, vpc-east",
+			AnnotationValidationString: "ok",
+			AnnotationPolicy:           "on-call",
+		},
+	}}
+	targets := []Target{
+		{Suffix: "shop.example.com/", URI: "https://shop.example.com/"},
+		{Suffix: "shop.example.com/api", URI: "https://shop.example.com/api"},
+	}
+
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets); err != nil {
+		t.Fatal(err)
+	}
+	items := monitors(t, c)
+	if len(items) != 3 {
+		t.Fatalf("expected two generated monitors, got %v", items)
+	}
+
+	api, ok := items["web-shop.example.com-api"]
+	if !ok {
+		t.Fatalf("expected a monitor named after the host and path, got %v", items)
+	}
+	if *api.Spec.URI != "https://shop.example.com/api" || *api.Spec.Frequency != 5 || len(api.Spec.Locations) != 2 ||
+		*api.Spec.Locations[1] != "vpc-east" || *api.Spec.Options.ValidationString != "ok" ||
+		api.Spec.Conditions[0].PolicyRef.Name != "on-call" || *api.Spec.Type != "SIMPLE" {
+		t.Fatalf("unexpected spec %+v", api.Spec)
+	}
+	if !metav1.IsControlledBy(&api, ingress) || api.Labels[LabelOwnerUID] != "web-uid" {
+		t.Fatal("expected the monitor to be owned by the ingress")
+	}
+
+	// a removed path deletes its monitor
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := monitors(t, c)["web-shop.example.com-api"]; ok {
+		t.Fatal("expected the monitor of the removed path to be deleted")
+	}
+
+	// a monitor created by hand is never taken over
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, []Target{{Suffix: "manual", URI: "https://manual"}}); err == nil {
+		t.Fatal("expected the monitor created by hand to be left alone")
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning MonitorFailed") {
+		t.Fatalf("unexpected event %s", event)
+	}
+
+	ingress.Annotations[AnnotationFrequency] = "often"
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets); err != nil {
+		t.Fatal(err)
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning InvalidAnnotation") {
+		t.Fatalf("unexpected event %s", event)
+	}
+
+	// removing the annotation deletes the generated monitors only
+	delete(ingress.Annotations, AnnotationMonitor)
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets); err != nil {
+		t.Fatal(err)
+	}
+	if items := monitors(t, c); len(items) != 1 || items["web-manual"].Name == "" {
+		t.Fatalf("expected only the monitor created by hand to remain, got %v", items)
+	}
+}
+
+func TestApplyLongName(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := networkingv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewFakeClientWithScheme(s)
+	recorder := record.NewFakeRecorder(10)
+	ctx := context.TODO()
+
+	// names can be up to 253 characters, label values only up to 63
+	ingress := &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:        strings.Repeat("storefront-", 10) + "web",
+		Namespace:   "team",
+		UID:         types.UID("6c2f4b9e-3f0a-4a8e-9d2b-1c5e7f9a0b3d"),
+		Annotations: map[string]string{AnnotationMonitor: "true"},
+	}}
+	targets := []Target{{Suffix: "shop.example.com/", URI: "https://shop.example.com/"}}
+
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets); err != nil {
+		t.Fatal(err)
+	}
+	items := monitors(t, c)
+	if len(items) != 1 {
+		t.Fatalf("expected a generated monitor, got %v", items)
+	}
+	for _, monitor := range items {
+		if errs := validation.IsDNS1123Subdomain(monitor.Name); len(errs) > 0 {
+			t.Fatalf("invalid monitor name %s: %v", monitor.Name, errs)
+		}
+		for key, value := range monitor.Labels {
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				t.Fatalf("invalid value of label %s: %v", key, errs)
+			}
+		}
+	}
+
+	delete(ingress.Annotations, AnnotationMonitor)
+	if err := Apply(ctx, c, s, recorder, "Ingress", ingress, targets); err != nil {
+		t.Fatal(err)
+	}
+	if items := monitors(t, c); len(items) != 0 {
+		t.Fatalf("expected the monitor to be pruned, got %v", items)
+	}
+}
@@ -1,16 +1,18 @@
 package controller
 
 import (
+	"github.com/sstarcher/newrelic-operator/pkg/account"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
+var AddToManagerFuncs []func(manager.Manager, *account.Resolver) error
 
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
+// AddToManager adds all Controllers to the Manager, accounts is shared by all of them so
+// New Relic clients are only built once per account
+func AddToManager(m manager.Manager, accounts *account.Resolver) error {
 	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
+		if err := f(m, accounts); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"testing"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/dependents"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newDashboard(namespace string, name string, configMap string) *newrelicv1alpha1.Dashboard {
+	dashboard := &newrelicv1alpha1.Dashboard{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	if configMap != "" {
+		dashboard.Spec.JSONFrom = &newrelicv1alpha1.DashboardJSONSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+				Key:                  "dashboard.json",
+			},
+		}
+	}
+	return dashboard
+}
+
+func TestConfigMapWatch(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewFakeClientWithScheme(s,
+		newDashboard("team", "uses", "dashboards"),
+		newDashboard("team", "inline", ""),
+		newDashboard("team", "other", "other"),
+		newDashboard("elsewhere", "uses", "dashboards"),
+	)
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "dashboards", Namespace: "team"}}
+	requests := dependents.OfConfigMap(c, &newrelicv1alpha1.DashboardList{})(handler.MapObject{Meta: configMap, Object: configMap})
+
+	if len(requests) != 1 || requests[0].Namespace != "team" || requests[0].Name != "uses" {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+}
@@ -0,0 +1,215 @@
+// Package dependents requeues resources when a resource they refer to is created in New Relic,
+// when a ConfigMap or Secret they read changes, or when the NewRelicAccount they are synced with changes
+package dependents
+
+import (
+	"context"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("dependents")
+
+// IDChanged passes creation, deletion and updates that change the New Relic ID of a resource
+var IDChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		old, ok := e.ObjectOld.(newrelicv1alpha1.CRD)
+		if !ok {
+			return false
+		}
+		current, ok := e.ObjectNew.(newrelicv1alpha1.CRD)
+		if !ok {
+			return false
+		}
+
+		oldID, currentID := old.GetStatus().GetID(), current.GetStatus().GetID()
+		if oldID == nil || currentID == nil {
+			return oldID != currentID
+		}
+		return *oldID != *currentID
+	},
+}
+
+// UsesAlertPolicy reports if item refers to the AlertPolicy resource namespace/name
+func UsesAlertPolicy(item runtime.Object, namespace string, name string) bool {
+	user, ok := item.(interface{ UsesAlertPolicy(string, string) bool })
+	return ok && user.UsesAlertPolicy(namespace, name)
+}
+
+// UsesAlertChannel reports if item refers to the AlertChannel resource namespace/name
+func UsesAlertChannel(item runtime.Object, namespace string, name string) bool {
+	user, ok := item.(interface{ UsesAlertChannel(string, string) bool })
+	return ok && user.UsesAlertChannel(namespace, name)
+}
+
+// Of maps a resource to the items of list in any namespace that use it
+func Of(c client.Client, list runtime.Object, uses func(runtime.Object, string, string) bool) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		return matching(c, list, metav1.NamespaceAll, func(item runtime.Object) bool {
+			return uses(item, obj.Meta.GetNamespace(), obj.Meta.GetName())
+		})
+	}
+}
+
+// SecretUser is implemented by resources reading values from Secrets in their namespace
+type SecretUser interface {
+	UsesSecret(name string) bool
+}
+
+// ConfigMapUser is implemented by resources reading values from ConfigMaps in their namespace
+type ConfigMapUser interface {
+	UsesConfigMap(name string) bool
+}
+
+var (
+	_ SecretUser    = &newrelicv1alpha1.AlertChannel{}
+	_ SecretUser    = &newrelicv1alpha1.Dashboard{}
+	_ SecretUser    = &newrelicv1alpha1.Monitor{}
+	_ SecretUser    = &newrelicv1alpha1.SecureCredential{}
+	_ ConfigMapUser = &newrelicv1alpha1.Dashboard{}
+	_ ConfigMapUser = &newrelicv1alpha1.Monitor{}
+)
+
+// WatchSecrets requeues the items of list, which have to be SecretUsers, when a Secret they read changes
+func WatchSecrets(c controller.Controller, cl client.Client, list runtime.Object) error {
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: OfSecret(cl, list),
+	})
+}
+
+// WatchConfigMaps requeues the items of list, which have to be ConfigMapUsers, when a ConfigMap they read changes
+func WatchConfigMaps(c controller.Controller, cl client.Client, list runtime.Object) error {
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: OfConfigMap(cl, list),
+	})
+}
+
+// OfSecret maps a Secret to the items of list in its namespace that read from it
+func OfSecret(c client.Client, list runtime.Object) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		return matching(c, list, obj.Meta.GetNamespace(), func(item runtime.Object) bool {
+			user, ok := item.(SecretUser)
+			return ok && user.UsesSecret(obj.Meta.GetName())
+		})
+	}
+}
+
+// OfConfigMap maps a ConfigMap to the items of list in its namespace that read from it
+func OfConfigMap(c client.Client, list runtime.Object) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		return matching(c, list, obj.Meta.GetNamespace(), func(item runtime.Object) bool {
+			user, ok := item.(ConfigMapUser)
+			return ok && user.UsesConfigMap(obj.Meta.GetName())
+		})
+	}
+}
+
+// WatchAccounts requeues the items of list when the NewRelicAccount they use or the Secret holding its credentials changes
+func WatchAccounts(c controller.Controller, cl client.Client, list runtime.Object) error {
+	err := c.Watch(&source.Kind{Type: &newrelicv1alpha1.NewRelicAccount{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: OfAccount(cl, list),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: OfAccountSecret(cl, list),
+	})
+}
+
+// OfAccount maps a NewRelicAccount to the items of list in its namespace that use it
+func OfAccount(c client.Client, list runtime.Object) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		return usingAccounts(c, list, obj.Meta.GetNamespace(), map[string]bool{obj.Meta.GetName(): true})
+	}
+}
+
+// OfAccountSecret maps a Secret to the items of list in its namespace that use a NewRelicAccount reading it
+func OfAccountSecret(c client.Client, list runtime.Object) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		accounts := &newrelicv1alpha1.NewRelicAccountList{}
+		err := c.List(context.TODO(), accounts, client.InNamespace(obj.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "unable to list accounts", "Namespace", obj.Meta.GetNamespace())
+			return nil
+		}
+
+		names := map[string]bool{}
+		for _, account := range accounts.Items {
+			if account.Spec.SecretRef.Name == obj.Meta.GetName() {
+				names[account.Name] = true
+			}
+		}
+		if len(names) == 0 {
+			return nil
+		}
+		return usingAccounts(c, list, obj.Meta.GetNamespace(), names)
+	}
+}
+
+// usingAccounts returns requests for the items of list in namespace that use one of the named accounts
+func usingAccounts(c client.Client, list runtime.Object, namespace string, names map[string]bool) []reconcile.Request {
+	return matching(c, list, namespace, func(item runtime.Object) bool {
+		user, ok := item.(interface {
+			GetAccountRef() *corev1.LocalObjectReference
+		})
+		if !ok {
+			return false
+		}
+
+		name := newrelicv1alpha1.DefaultAccountName
+		if ref := user.GetAccountRef(); ref != nil {
+			name = ref.Name
+		}
+		return names[name]
+	})
+}
+
+// matching returns requests for the items of list in namespace that uses reports true for, all namespaces are
+// listed when namespace is empty
+func matching(c client.Client, list runtime.Object, namespace string, uses func(runtime.Object) bool) []reconcile.Request {
+	items := list.DeepCopyObject()
+	err := c.List(context.TODO(), items, client.InNamespace(namespace))
+	if err != nil {
+		log.Error(err, "unable to list dependents", "Namespace", namespace)
+		return nil
+	}
+
+	objects, err := meta.ExtractList(items)
+	if err != nil {
+		log.Error(err, "unable to read dependents")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range objects {
+		if !uses(item) {
+			continue
+		}
+
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			log.Error(err, "unable to read dependent")
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: accessor.GetNamespace(),
+			Name:      accessor.GetName(),
+		}})
+	}
+	return requests
+}
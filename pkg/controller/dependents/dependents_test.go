@@ -0,0 +1,141 @@
+package dependents
+
+import (
+	"testing"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func newCondition(namespace string, name string, ref *newrelicv1alpha1.ObjectReference) *newrelicv1alpha1.NrqlAlertCondition {
+	return &newrelicv1alpha1.NrqlAlertCondition{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       newrelicv1alpha1.NrqlAlertConditionSpec{PolicyName: "by-name", PolicyRef: ref},
+	}
+}
+
+func TestOf(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewFakeClientWithScheme(s,
+		newCondition("team", "uses", &newrelicv1alpha1.ObjectReference{Name: "policy"}),
+		newCondition("team", "by-name", nil),
+		newCondition("team", "other", &newrelicv1alpha1.ObjectReference{Name: "other"}),
+		newCondition("elsewhere", "local", &newrelicv1alpha1.ObjectReference{Name: "policy"}),
+		newCondition("elsewhere", "remote", &newrelicv1alpha1.ObjectReference{Name: "policy", Namespace: "team"}),
+	)
+
+	policy := &newrelicv1alpha1.AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "team"}}
+	requests := Of(c, &newrelicv1alpha1.NrqlAlertConditionList{}, UsesAlertPolicy)(handler.MapObject{Meta: policy, Object: policy})
+
+	if len(requests) != 2 {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+	for _, request := range requests {
+		if request.String() != "team/uses" && request.String() != "elsewhere/remote" {
+			t.Fatalf("unexpected request %v", request)
+		}
+	}
+}
+
+func newCredential(namespace string, name string, secret string) *newrelicv1alpha1.SecureCredential {
+	return &newrelicv1alpha1.SecureCredential{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: newrelicv1alpha1.SecureCredentialSpec{ValueFrom: newrelicv1alpha1.SecureCredentialSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Key: "value"},
+		}},
+	}
+}
+
+func TestOfSecret(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewFakeClientWithScheme(s,
+		newCredential("team", "uses", "credentials"),
+		newCredential("team", "other", "other"),
+		newCredential("elsewhere", "uses", "credentials"),
+	)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "team"}}
+	requests := OfSecret(c, &newrelicv1alpha1.SecureCredentialList{})(handler.MapObject{Meta: secret, Object: secret})
+	if len(requests) != 1 || requests[0].Namespace != "team" || requests[0].Name != "uses" {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+
+	// the credentials read the Secret, not a ConfigMap of the same name
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "team"}}
+	requests = OfConfigMap(c, &newrelicv1alpha1.SecureCredentialList{})(handler.MapObject{Meta: configMap, Object: configMap})
+	if len(requests) != 0 {
+		t.Fatalf("unexpected requests for a ConfigMap %v", requests)
+	}
+}
+
+func TestIDChanged(t *testing.T) {
+	old := &newrelicv1alpha1.AlertPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}}
+	current := old.DeepCopy()
+	if IDChanged.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: current, ObjectNew: current}) {
+		t.Fatal("expected update without an ID change to be ignored")
+	}
+
+	current.Status.SetID(1)
+	if !IDChanged.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: current, ObjectNew: current}) {
+		t.Fatal("expected a new ID to requeue dependents")
+	}
+}
+
+func TestOfAccountSecret(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	withAccount := func(name string, account string) *newrelicv1alpha1.NrqlAlertCondition {
+		condition := newCondition("team", name, nil)
+		if account != "" {
+			condition.Spec.AccountRef = &corev1.LocalObjectReference{Name: account}
+		}
+		return condition
+	}
+	account := func(name string, secret string) *newrelicv1alpha1.NewRelicAccount {
+		return &newrelicv1alpha1.NewRelicAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+			Spec:       newrelicv1alpha1.NewRelicAccountSpec{SecretRef: corev1.LocalObjectReference{Name: secret}},
+		}
+	}
+
+	c := fake.NewFakeClientWithScheme(s,
+		account(newrelicv1alpha1.DefaultAccountName, "credentials"),
+		account("eu", "eu-credentials"),
+		withAccount("default", ""),
+		withAccount("eu", "eu"),
+		newCondition("elsewhere", "default", nil),
+	)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "eu-credentials", Namespace: "team"}}
+	requests := OfAccountSecret(c, &newrelicv1alpha1.NrqlAlertConditionList{})(handler.MapObject{Meta: secret, Object: secret})
+	if len(requests) != 1 || requests[0].String() != "team/eu" {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+
+	secret.Name = "credentials"
+	requests = OfAccountSecret(c, &newrelicv1alpha1.NrqlAlertConditionList{})(handler.MapObject{Meta: secret, Object: secret})
+	if len(requests) != 1 || requests[0].String() != "team/default" {
+		t.Fatalf("expected only the resource without accountRef in the namespace, got %v", requests)
+	}
+
+	secret.Name = "unrelated"
+	if requests := OfAccountSecret(c, &newrelicv1alpha1.NrqlAlertConditionList{})(handler.MapObject{Meta: secret, Object: secret}); len(requests) != 0 {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+}
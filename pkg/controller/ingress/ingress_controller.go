@@ -0,0 +1,153 @@
+package ingress
+
+import (
+	"context"
+	"strings"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/automonitor"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_ingress")
+
+const kind = "Ingress"
+
+// Add creates a new Ingress Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileIngress{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor("ingress-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("ingress-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Ingress
+	// Annotations do not change the generation, so every update is reconciled
+	err = c.Watch(&source.Kind{Type: &networkingv1beta1.Ingress{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the generated Monitors to revert edits and recreate deleted ones
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.Monitor{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &networkingv1beta1.Ingress{},
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// targets returns a URL for each host and path of the rules, https is used for hosts listed in the TLS section
+func targets(ingress *networkingv1beta1.Ingress) []automonitor.Target {
+	tls := map[string]bool{}
+	for _, item := range ingress.Spec.TLS {
+		for _, host := range item.Hosts {
+			tls[host] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	targets := []automonitor.Target{}
+	for _, rule := range ingress.Spec.Rules {
+		// rules without a host or with a wildcard host have no URL to check
+		if rule.Host == "" || strings.HasPrefix(rule.Host, "*") {
+			continue
+		}
+
+		scheme := "http"
+		if tls[rule.Host] {
+			scheme = "https"
+		}
+
+		paths := []string{"/"}
+		if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 {
+			paths = []string{}
+			for _, item := range rule.HTTP.Paths {
+				path := strings.TrimSuffix(item.Path, "*")
+				if path == "" {
+					path = "/"
+				}
+				paths = append(paths, path)
+			}
+		}
+
+		for _, path := range paths {
+			uri := scheme + "://" + rule.Host + path
+			if seen[uri] {
+				continue
+			}
+			seen[uri] = true
+			targets = append(targets, automonitor.Target{Suffix: rule.Host + path, URI: uri})
+		}
+	}
+	return targets
+}
+
+// blank assignment to verify that ReconcileIngress implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileIngress{}
+
+// ReconcileIngress generates Monitors for annotated Ingresses
+type ReconcileIngress struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a Ingress object and creates, updates or deletes the Monitors
+// generated from its annotations
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileIngress) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the Ingress instance
+	instance := &networkingv1beta1.Ingress{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// The generated Monitors are garbage collected through their owner reference.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	err = automonitor.Apply(context.TODO(), r.client, r.scheme, r.recorder, kind, instance, targets(instance))
+	if err != nil {
+		reqLogger.Error(err, "unable to generate monitors")
+	}
+	return reconcile.Result{}, err
+}
@@ -0,0 +1,32 @@
+package ingress
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/controller/automonitor"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+)
+
+func TestTargets(t *testing.T) {
+	ingress := &networkingv1beta1.Ingress{Spec: networkingv1beta1.IngressSpec{
+		TLS: []networkingv1beta1.IngressTLS{{Hosts: []string{"shop.example.com"}}},
+		Rules: []networkingv1beta1.IngressRule{
+			{Host: "shop.example.com", IngressRuleValue: networkingv1beta1.IngressRuleValue{HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+				Paths: []networkingv1beta1.HTTPIngressPath{{Path: "/api/*"}, {Path: "/api/"}, {Path: ""}},
+			}}},
+			{Host: "docs.example.com"},
+			{Host: "*.example.com"},
+			{},
+		},
+	}}
+
+	expected := []automonitor.Target{
+		{Suffix: "shop.example.com/api/", URI: "https://shop.example.com/api/"},
+		{Suffix: "shop.example.com/", URI: "https://shop.example.com/"},
+		{Suffix: "docs.example.com/", URI: "http://docs.example.com/"},
+	}
+	if got := targets(ingress); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected targets %v", got)
+	}
+}
@@ -0,0 +1,163 @@
+package monitorset
+
+import (
+	"context"
+	"reflect"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/automonitor"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_monitorset")
+
+const kind = "MonitorSet"
+
+// Add creates a new MonitorSet Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileMonitorSet{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("monitorset-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource MonitorSet
+	// Status is written by the controller itself, only react to spec changes and deletion
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.MonitorSet{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the generated Monitors to revert edits and recreate deleted ones
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.Monitor{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &newrelicv1alpha1.MonitorSet{},
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Services so sets selecting them follow their labels and load balancer addresses
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: usedBy(mgr.GetClient()),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// usedBy maps a Service to the sets in its namespace that select Services
+func usedBy(c client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		list := &newrelicv1alpha1.MonitorSetList{}
+		err := c.List(context.TODO(), list, client.InNamespace(obj.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "unable to list monitor sets", "Namespace", obj.Meta.GetNamespace())
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range list.Items {
+			if list.Items[i].UsesServices() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: list.Items[i].Namespace,
+					Name:      list.Items[i].Name,
+				}})
+			}
+		}
+		return requests
+	}
+}
+
+// blank assignment to verify that ReconcileMonitorSet implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileMonitorSet{}
+
+// ReconcileMonitorSet reconciles a MonitorSet object
+type ReconcileMonitorSet struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile reads that state of the cluster for a MonitorSet object and renders its Monitors, pruning the ones
+// that are no longer generated
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileMonitorSet) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the MonitorSet instance
+	instance := &newrelicv1alpha1.MonitorSet{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// The generated Monitors are garbage collected through their owner reference.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	original := instance.DeepCopy()
+	ctx := newrelicv1alpha1.WithKubeClient(context.TODO(), r.client)
+
+	monitors, err := instance.Monitors(ctx)
+	if err == nil {
+		err = automonitor.SyncMonitors(ctx, r.client, r.scheme, kind, instance, monitors)
+	}
+	if err != nil {
+		reqLogger.Error(err, "unable to generate monitors")
+		instance.MarkFailed(err)
+	} else {
+		instance.MarkSynced(monitors)
+	}
+
+	if !reflect.DeepEqual(original.Status, instance.Status) {
+		statusErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := &newrelicv1alpha1.MonitorSet{}
+			if err := r.client.Get(ctx, request.NamespacedName, latest); err != nil {
+				return err
+			}
+
+			latest.Status = instance.Status
+			return r.client.Status().Update(ctx, latest)
+		})
+		if statusErr != nil && !errors.IsNotFound(statusErr) {
+			return reconcile.Result{}, statusErr
+		}
+	}
+
+	// a spec that can not be rendered has to be fixed, retrying would not help
+	if newrelicv1alpha1.IsInvalidSpec(err) {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, err
+}
@@ -0,0 +1,157 @@
+package monitorset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := "https://$(env).example.com"
+	set := &newrelicv1alpha1.MonitorSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team", UID: types.UID("shop-uid")},
+		Spec: newrelicv1alpha1.MonitorSetSpec{
+			Template: newrelicv1alpha1.MonitorTemplate{Spec: newrelicv1alpha1.MonitorSpec{URI: &uri}},
+			Generators: []newrelicv1alpha1.MonitorSetGenerator{
+				{List: []map[string]string{{"env": "staging"}, {"env": "prod"}}},
+			},
+		},
+	}
+	c := fake.NewFakeClientWithScheme(s, set)
+	r := &ReconcileMonitorSet{client: c, scheme: s}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "shop"}}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := &newrelicv1alpha1.Monitor{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "team", Name: "shop-prod"}, monitor); err != nil {
+		t.Fatal(err)
+	}
+	if *monitor.Spec.URI != "https://prod.example.com" || monitor.OwnerReferences[0].Name != "shop" {
+		t.Fatalf("unexpected monitor %+v", monitor)
+	}
+
+	if err := c.Get(context.TODO(), request.NamespacedName, set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Status.Phase != newrelicv1alpha1.PhaseReady || len(set.Status.Monitors) != 2 {
+		t.Fatalf("unexpected status %+v", set.Status)
+	}
+
+	// children that are no longer generated are pruned
+	set.Spec.Generators[0].List = []map[string]string{{"env": "prod"}}
+	if err := c.Update(context.TODO(), set); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+
+	list := &newrelicv1alpha1.MonitorList{}
+	if err := c.List(context.TODO(), list, client.InNamespace("team")); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "shop-prod" {
+		t.Fatalf("expected only shop-prod to remain, got %v", list.Items)
+	}
+
+	// a template that can not be rendered is reported without requeueing
+	set.Spec.Template.Name = "$(region)"
+	if err := c.Update(context.TODO(), set); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), request.NamespacedName, set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Status.Phase != newrelicv1alpha1.PhaseFailed || set.Status.Conditions[0].Reason != newrelicv1alpha1.ReasonInvalidSpec {
+		t.Fatalf("unexpected status %+v", set.Status)
+	}
+}
+
+// conflictingClient fails the first status write with a conflict like the API server does when the object changed meanwhile
+type conflictingClient struct {
+	client.Client
+	conflicts int
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{c}
+}
+
+type conflictingStatusWriter struct {
+	*conflictingClient
+}
+
+func (w conflictingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	if w.conflicts > 0 {
+		w.conflicts--
+		return apierrors.NewConflict(schema.GroupResource{Resource: "monitorsets"}, "shop", errors.New("stale"))
+	}
+	return w.Client.Status().Update(ctx, obj, opts...)
+}
+
+func (w conflictingStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.Client.Status().Patch(ctx, obj, patch, opts...)
+}
+
+func TestReconcileStatusConflict(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := newrelicv1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := "https://$(env).example.com"
+	set := &newrelicv1alpha1.MonitorSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team", UID: types.UID("shop-uid")},
+		Spec: newrelicv1alpha1.MonitorSetSpec{
+			Template: newrelicv1alpha1.MonitorTemplate{Spec: newrelicv1alpha1.MonitorSpec{URI: &uri}},
+			Generators: []newrelicv1alpha1.MonitorSetGenerator{
+				{List: []map[string]string{{"env": "prod"}}},
+			},
+		},
+	}
+	c := &conflictingClient{Client: fake.NewFakeClientWithScheme(s, set), conflicts: 1}
+	r := &ReconcileMonitorSet{client: c, scheme: s}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "shop"}}
+
+	if _, err := r.Reconcile(request); err != nil {
+		t.Fatal(err)
+	}
+	if c.conflicts != 0 {
+		t.Fatal("expected the status write to be attempted")
+	}
+
+	if err := c.Get(context.TODO(), request.NamespacedName, set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Status.Phase != newrelicv1alpha1.PhaseReady || len(set.Status.Monitors) != 1 {
+		t.Fatalf("expected the status to be written after the conflict, got %+v", set.Status)
+	}
+}
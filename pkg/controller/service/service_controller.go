@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	newrelicv1alpha1 "github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/controller/automonitor"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_service")
+
+const kind = "Service"
+
+// Add creates a new Service Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileService{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor("service-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("service-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Service
+	// Annotations and the load balancer address do not change the generation, so every update is reconciled
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the generated Monitors to revert edits and recreate deleted ones
+	err = c.Watch(&source.Kind{Type: &newrelicv1alpha1.Monitor{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &corev1.Service{},
+	}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// targets returns a URL for each port of the load balancer, it is empty until the load balancer has an address
+func targets(service *corev1.Service) []automonitor.Target {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || len(service.Status.LoadBalancer.Ingress) == 0 {
+		return nil
+	}
+
+	address := service.Status.LoadBalancer.Ingress[0].Hostname
+	if address == "" {
+		address = service.Status.LoadBalancer.Ingress[0].IP
+	}
+
+	path := service.GetAnnotations()[automonitor.AnnotationPath]
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	targets := []automonitor.Target{}
+	for _, port := range service.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+
+		scheme := "http"
+		if port.Port == 443 || port.Name == "https" {
+			scheme = "https"
+		}
+
+		host := address
+		if !(scheme == "http" && port.Port == 80) && !(scheme == "https" && port.Port == 443) {
+			host = net.JoinHostPort(address, strconv.Itoa(int(port.Port)))
+		}
+
+		suffix := port.Name
+		if suffix == "" {
+			suffix = strconv.Itoa(int(port.Port))
+		}
+		targets = append(targets, automonitor.Target{Suffix: suffix, URI: scheme + "://" + host + path})
+	}
+	return targets
+}
+
+// blank assignment to verify that ReconcileService implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileService{}
+
+// ReconcileService generates Monitors for annotated Services of type LoadBalancer
+type ReconcileService struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a Service object and creates, updates or deletes the Monitors
+// generated from its annotations
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileService) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	// Fetch the Service instance
+	instance := &corev1.Service{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// The generated Monitors are garbage collected through their owner reference.
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	err = automonitor.Apply(context.TODO(), r.client, r.scheme, r.recorder, kind, instance, targets(instance))
+	if err != nil {
+		reqLogger.Error(err, "unable to generate monitors")
+	}
+	return reconcile.Result{}, err
+}
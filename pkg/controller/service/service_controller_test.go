@@ -0,0 +1,43 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/controller/automonitor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTargets(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{automonitor.AnnotationPath: "healthz"}},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			Ports: []corev1.ServicePort{
+				{Name: "https", Port: 443},
+				{Name: "admin", Port: 8080},
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+				{Port: 80},
+			},
+		},
+	}
+	if got := targets(service); len(got) != 0 {
+		t.Fatalf("expected no targets before the load balancer has an address, got %v", got)
+	}
+
+	service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+	expected := []automonitor.Target{
+		{Suffix: "https", URI: "https://203.0.113.10/healthz"},
+		{Suffix: "admin", URI: "http://203.0.113.10:8080/healthz"},
+		{Suffix: "80", URI: "http://203.0.113.10/healthz"},
+	}
+	if got := targets(service); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected targets %v", got)
+	}
+
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	if got := targets(service); len(got) != 0 {
+		t.Fatalf("expected no targets for a ClusterIP service, got %v", got)
+	}
+}
@@ -0,0 +1,492 @@
+// Package export writes the objects of a New Relic account as operator resources
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// Options select what is exported and how the resources are written
+type Options struct {
+	// Namespace is set on every resource when not empty
+	Namespace string
+	// AccountRef is set as spec.accountRef on every resource when not empty
+	AccountRef string
+	// NameFilter only exports objects whose name matches when set
+	NameFilter *regexp.Regexp
+	// Warnings receives the objects that can not be exported, they are dropped when nil
+	Warnings io.Writer
+}
+
+// manifest is the part of a resource that is written out, status and server fields are left off
+type manifest struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       string      `json:"kind"`
+	Metadata   metadata    `json:"metadata"`
+	Spec       interface{} `json:"spec"`
+}
+
+type metadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type exporter struct {
+	options Options
+	out     io.Writer
+	written int
+	// names are the resource names already written, by kind
+	names map[string]bool
+}
+
+// Export writes alert channels, alert policies, dashboards and monitors from the account to out
+// as a multi document YAML stream. Every resource sets spec.importID so applying it adopts the
+// existing object instead of creating a new one.
+func Export(client *newrelic.Client, out io.Writer, options Options) error {
+	e := &exporter{options: options, out: out, names: map[string]bool{}}
+
+	channels, err := client.Alerts.ListChannels()
+	if err != nil {
+		return fmt.Errorf("unable to list alert channels %w", err)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Name < channels[j].Name })
+
+	policies, err := client.Alerts.ListPolicies(nil)
+	if err != nil {
+		return fmt.Errorf("unable to list alert policies %w", err)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+
+	for _, channel := range channels {
+		name, ok := e.resourceName("AlertChannel", channel.Name, strconv.Itoa(channel.ID))
+		if !ok {
+			continue
+		}
+
+		spec, keys, err := alertChannelSpec(channel, name)
+		if err != nil {
+			e.warn("skipping AlertChannel %q, %v", channel.Name, err)
+			continue
+		}
+		if len(keys) > 0 {
+			e.warn("AlertChannel %s reads %s from the Secret %s, create it before applying", name, strings.Join(keys, ", "), name)
+		}
+
+		if err := e.write("AlertChannel", channel.Name, name, spec); err != nil {
+			return err
+		}
+	}
+
+	for _, policy := range policies {
+		name, ok := e.resourceName("AlertPolicy", policy.Name, strconv.Itoa(policy.ID))
+		if !ok {
+			continue
+		}
+		if err := e.write("AlertPolicy", policy.Name, name, alertPolicySpec(policy, channels)); err != nil {
+			return err
+		}
+	}
+
+	items, err := client.Dashboards.ListDashboards(nil)
+	if err != nil {
+		return fmt.Errorf("unable to list dashboards %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Title < items[j].Title })
+
+	for _, dashboard := range items {
+		name, ok := e.resourceName("Dashboard", dashboard.Title, strconv.Itoa(dashboard.ID))
+		if !ok {
+			continue
+		}
+		if err := e.write("Dashboard", dashboard.Title, name, dashboardSpec(dashboard)); err != nil {
+			return err
+		}
+	}
+
+	monitors, err := client.Synthetics.ListMonitors()
+	if err != nil {
+		return fmt.Errorf("unable to list monitors %w", err)
+	}
+	sort.Slice(monitors, func(i, j int) bool { return monitors[i].Name < monitors[j].Name })
+
+	conditions, err := monitorConditions(client, policies)
+	if err != nil {
+		return err
+	}
+
+	for _, monitor := range monitors {
+		name, ok := e.resourceName("Monitor", monitor.Name, monitor.ID)
+		if !ok {
+			continue
+		}
+
+		spec := monitorSpec(monitor, conditions[monitor.ID])
+		if err := monitorScript(client, monitor, spec); err != nil {
+			return err
+		}
+		if err := e.write("Monitor", monitor.Name, name, spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resourceName returns the name of the resource for the New Relic object named name, it is false when
+// the name does not pass the filter. Names that are not valid resource names are turned into one and
+// names written before are told apart by the ID of the object.
+func (e *exporter) resourceName(kind string, name string, id string) (string, bool) {
+	if e.options.NameFilter != nil && !e.options.NameFilter.MatchString(name) {
+		return "", false
+	}
+
+	resource := name
+	if len(validation.IsDNS1123Subdomain(name)) > 0 {
+		// dots are replaced too, a dot next to a dash is not valid
+		resource = v1alpha1.MonitorName(strings.ReplaceAll(name, ".", "-"))
+	}
+	if resource == "" {
+		resource = v1alpha1.MonitorName(kind, id)
+	}
+	if e.names[kind+"/"+resource] {
+		resource = v1alpha1.MonitorName(resource, id)
+	}
+	if resource != name {
+		e.warn("writing %s %q as %s", kind, name, resource)
+	}
+
+	e.names[kind+"/"+resource] = true
+	return resource, true
+}
+
+// write adds the resource to the output, the New Relic name is set in the spec when it differs from the resource name
+func (e *exporter) write(kind string, name string, resource string, spec interface{}) error {
+	if resource != name {
+		setName(spec, name)
+	}
+
+	if e.options.AccountRef != "" {
+		setAccountRef(spec, &corev1.LocalObjectReference{Name: e.options.AccountRef})
+	}
+
+	data, err := yaml.Marshal(manifest{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       kind,
+		Metadata:   metadata{Name: resource, Namespace: e.options.Namespace},
+		Spec:       spec,
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.written > 0 {
+		if _, err := io.WriteString(e.out, "---\n"); err != nil {
+			return err
+		}
+	}
+	e.written++
+
+	_, err = e.out.Write(data)
+	return err
+}
+
+func (e *exporter) warn(format string, args ...interface{}) {
+	if e.options.Warnings != nil {
+		fmt.Fprintf(e.options.Warnings, format+"\n", args...)
+	}
+}
+
+func setAccountRef(spec interface{}, ref *corev1.LocalObjectReference) {
+	switch s := spec.(type) {
+	case *v1alpha1.AlertChannelSpec:
+		s.AccountRef = ref
+	case *v1alpha1.AlertPolicySpec:
+		s.AccountRef = ref
+	case *v1alpha1.DashboardSpec:
+		s.AccountRef = ref
+	case *v1alpha1.MonitorSpec:
+		s.AccountRef = ref
+	}
+}
+
+func setName(spec interface{}, name string) {
+	switch s := spec.(type) {
+	case *v1alpha1.AlertChannelSpec:
+		s.Name = name
+	case *v1alpha1.AlertPolicySpec:
+		s.Name = name
+	case *v1alpha1.DashboardSpec:
+		s.Title = name
+	case *v1alpha1.MonitorSpec:
+		s.Name = name
+	}
+}
+
+func importID(id int) *string {
+	value := strconv.Itoa(id)
+	return &value
+}
+
+// alertChannelSpec maps the channel onto its typed configuration, credentials are not written out but read
+// from keys of the Secret named secret, the keys are returned
+func alertChannelSpec(channel *alerts.Channel, secret string) (*v1alpha1.AlertChannelSpec, []string, error) {
+	spec := &v1alpha1.AlertChannelSpec{ImportID: importID(channel.ID)}
+	configuration := channel.Configuration
+
+	keys := []string{}
+	fromSecret := func(key string) v1alpha1.ConfigurationValue {
+		keys = append(keys, key)
+		return v1alpha1.ConfigurationValue{ValueFrom: &v1alpha1.ConfigurationValueSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret}, Key: key},
+		}}
+	}
+
+	switch channel.Type {
+	case alerts.ChannelTypes.Email:
+		attachment, _ := strconv.ParseBool(configuration.IncludeJSONAttachment)
+		spec.Email = &v1alpha1.EmailChannel{Recipients: split(configuration.Recipients), IncludeJSONAttachment: attachment}
+
+	case alerts.ChannelTypes.Slack:
+		spec.Slack = &v1alpha1.SlackChannel{URL: fromSecret("url"), Channel: configuration.Channel}
+
+	case alerts.ChannelTypes.PagerDuty:
+		spec.PagerDuty = &v1alpha1.PagerDutyChannel{ServiceKey: fromSecret("serviceKey")}
+
+	case alerts.ChannelTypes.OpsGenie:
+		spec.OpsGenie = &v1alpha1.OpsGenieChannel{
+			APIKey:     fromSecret("apiKey"),
+			Teams:      split(configuration.Teams),
+			Tags:       split(configuration.Tags),
+			Recipients: split(configuration.Recipients),
+			Region:     configuration.Region,
+		}
+
+	case alerts.ChannelTypes.VictorOps:
+		spec.VictorOps = &v1alpha1.VictorOpsChannel{Key: fromSecret("key"), RouteKey: configuration.RouteKey}
+
+	case alerts.ChannelTypes.Webhook:
+		webhook := &v1alpha1.WebhookChannel{BaseURL: configuration.BaseURL, AuthUsername: configuration.AuthUsername}
+		if configuration.AuthPassword != "" {
+			password := fromSecret("authPassword")
+			webhook.AuthPassword = &password
+		}
+		if len(configuration.Payload) > 0 {
+			webhook.PayloadType = configuration.PayloadType
+			webhook.Payload = map[string]string{}
+			for key, value := range configuration.Payload {
+				webhook.Payload[key] = payloadValue(value)
+			}
+		}
+
+		// headers often carry tokens, they are all read from the Secret
+		headers := []string{}
+		for key := range configuration.Headers {
+			headers = append(headers, key)
+		}
+		sort.Strings(headers)
+		for _, key := range headers {
+			if webhook.Headers == nil {
+				webhook.Headers = map[string]v1alpha1.ConfigurationValue{}
+			}
+			webhook.Headers[key] = fromSecret(key)
+		}
+		spec.Webhook = webhook
+
+	case alerts.ChannelTypes.User:
+		spec.User = &v1alpha1.UserChannel{UserID: configuration.UserID}
+
+	default:
+		return nil, nil, fmt.Errorf("the channel type %s is not supported", channel.Type)
+	}
+
+	return spec, keys, nil
+}
+
+// split returns the items of a comma separated setting
+func split(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
+
+// payloadValue writes values of a webhook payload that are not strings as JSON
+func payloadValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func alertPolicySpec(policy alerts.Policy, channels []*alerts.Channel) *v1alpha1.AlertPolicySpec {
+	spec := &v1alpha1.AlertPolicySpec{
+		IncidentPreference: string(policy.IncidentPreference),
+		ImportID:           importID(policy.ID),
+	}
+
+	for _, channel := range channels {
+		for _, id := range channel.Links.PolicyIDs {
+			if id == policy.ID {
+				spec.Channels = append(spec.Channels, channel.Name)
+				break
+			}
+		}
+	}
+
+	return spec
+}
+
+func dashboardSpec(dashboard *dashboards.Dashboard) *v1alpha1.DashboardSpec {
+	spec := &v1alpha1.DashboardSpec{
+		Icon:            string(dashboard.Icon),
+		Visibility:      string(dashboard.Visibility),
+		Editable:        string(dashboard.Editable),
+		GridColumnCount: int(dashboard.GridColumnCount),
+		ImportID:        importID(dashboard.ID),
+	}
+
+	if len(dashboard.Filter.EventTypes) > 0 || len(dashboard.Filter.Attributes) > 0 {
+		spec.Filter = &v1alpha1.DashboardFilter{
+			EventTypes: dashboard.Filter.EventTypes,
+			Attributes: dashboard.Filter.Attributes,
+		}
+	}
+
+	for _, widget := range dashboard.Widgets {
+		item := v1alpha1.DashboardWidget{
+			Title:                widget.Presentation.Title,
+			Notes:                widget.Presentation.Notes,
+			DrilldownDashboardID: widget.Presentation.DrilldownDashboardID,
+			Visualization:        string(widget.Visualization),
+			Layout: v1alpha1.DashboardWidgetLayout{
+				Row:    widget.Layout.Row,
+				Column: widget.Layout.Column,
+				Width:  widget.Layout.Width,
+				Height: widget.Layout.Height,
+			},
+		}
+
+		if len(widget.Data) > 0 {
+			item.NRQL = widget.Data[0].NRQL
+			item.Source = widget.Data[0].Source
+			item.Facet = widget.Data[0].Facet
+		}
+
+		if threshold := widget.Presentation.Threshold; threshold != nil {
+			red, yellow := threshold.Red, threshold.Yellow
+			item.Threshold = &v1alpha1.DashboardWidgetThreshold{Red: &red, Yellow: &yellow}
+		}
+
+		spec.Widgets = append(spec.Widgets, item)
+	}
+
+	return spec
+}
+
+// monitorConditions returns the synthetics conditions of each monitor ID
+func monitorConditions(client *newrelic.Client, policies []alerts.Policy) (map[string][]v1alpha1.Conditions, error) {
+	result := map[string][]v1alpha1.Conditions{}
+
+	for _, policy := range policies {
+		conditions, err := client.Alerts.ListSyntheticsConditions(policy.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list synthetics conditions for policy %s %w", policy.Name, err)
+		}
+
+		for _, condition := range conditions {
+			item := v1alpha1.Conditions{PolicyName: policy.Name}
+			if condition.Name != "Check Failure" {
+				name := condition.Name
+				item.Name = &name
+			}
+			if !condition.Enabled {
+				enabled := false
+				item.Enabled = &enabled
+			}
+			if condition.RunbookURL != "" {
+				runbook := condition.RunbookURL
+				item.RunbookURL = &runbook
+			}
+			result[condition.MonitorID] = append(result[condition.MonitorID], item)
+		}
+	}
+
+	return result, nil
+}
+
+// monitorScript adds the script of scripted monitors to the spec
+func monitorScript(client *newrelic.Client, monitor *synthetics.Monitor, spec *v1alpha1.MonitorSpec) error {
+	if monitor.Type != synthetics.MonitorTypes.APITest && monitor.Type != synthetics.MonitorTypes.ScriptedBrowser {
+		return nil
+	}
+
+	script, err := client.Synthetics.GetMonitorScript(monitor.ID)
+	if err != nil {
+		return fmt.Errorf("unable to read the script of monitor %s %w", monitor.Name, err)
+	}
+	spec.Script = &v1alpha1.Script{ScriptText: &script.Text}
+	return nil
+}
+
+func monitorSpec(monitor *synthetics.Monitor, conditions []v1alpha1.Conditions) *v1alpha1.MonitorSpec {
+	monitorType := string(monitor.Type)
+	frequency := int64(monitor.Frequency)
+	status := v1alpha1.MonitorStatusString(strings.ToLower(string(monitor.Status)))
+	id := monitor.ID
+
+	spec := &v1alpha1.MonitorSpec{
+		Type:       &monitorType,
+		Frequency:  &frequency,
+		Status:     &status,
+		Conditions: conditions,
+		ImportID:   &id,
+		Options: v1alpha1.MonitorOptions{
+			VerifySSL:              monitor.Options.VerifySSL,
+			BypassHEADRequest:      monitor.Options.BypassHEADRequest,
+			TreatRedirectAsFailure: monitor.Options.TreatRedirectAsFailure,
+		},
+	}
+
+	if monitor.URI != "" {
+		uri := monitor.URI
+		spec.URI = &uri
+	}
+
+	if monitor.SLAThreshold != 0 {
+		threshold := monitor.SLAThreshold
+		spec.SLAThreshold = &threshold
+	}
+
+	if monitor.Options.ValidationString != "" {
+		value := monitor.Options.ValidationString
+		spec.Options.ValidationString = &value
+	}
+
+	for _, location := range monitor.Locations {
+		location := location
+		spec.Locations = append(spec.Locations, &location)
+	}
+
+	return spec
+}
@@ -0,0 +1,195 @@
+package export
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	"sigs.k8s.io/yaml"
+)
+
+var responses = map[string]string{
+	"/alerts_channels.json": `{"channels": [
+		{"id": 1, "name": "slack", "type": "slack", "configuration": {"channel": "#alerts", "url": "https://hooks.slack.com/x"}, "links": {"policy_ids": [10]}},
+		{"id": 2, "name": "campfire", "type": "campfire", "configuration": {"room": "ops"}}
+	]}`,
+	"/alerts_policies.json": `{"policies": [
+		{"id": 10, "name": "production", "incident_preference": "PER_POLICY"},
+		{"id": 11, "name": "Hand Made Policy", "incident_preference": "PER_POLICY"}
+	]}`,
+	"/alerts_synthetics_conditions.json": `{"synthetics_conditions": [
+		{"id": 100, "name": "website", "enabled": true, "monitor_id": "abc-123"}
+	]}`,
+	"/dashboards.json": `{"dashboards": [
+		{"id": 20, "title": "overview", "icon": "bar-chart", "visibility": "all", "editable": "read_only", "widgets": [
+			{"widget_id": 1, "visualization": "billboard", "data": [{"nrql": "SELECT count(*) FROM Transaction"}], "presentation": {"title": "Requests"}, "layout": {"row": 1, "column": 1, "width": 1, "height": 1}}
+		]}
+	]}`,
+	"/v4/monitors": `{"monitors": [
+		{"id": "abc-123", "name": "website", "type": "SIMPLE", "frequency": 5, "uri": "https://example.com", "locations": ["AWS_US_WEST_1"], "status": "ENABLED", "slaThreshold": 7},
+		{"id": "def-456", "name": "login", "type": "SCRIPT_BROWSER", "frequency": 15, "locations": ["AWS_US_WEST_1"], "status": "ENABLED"}
+	]}`,
+	// $browser.get('https://example.com/login')
+	"/v4/monitors/def-456/script": `{"scriptText": "JGJyb3dzZXIuZ2V0KCdodHRwczovL2V4YW1wbGUuY29tL2xvZ2luJyk="}`,
+}
+
+func newTestClient(t *testing.T) *newrelic.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// conditions only exist on the production policy
+		if r.URL.Path == "/alerts_synthetics_conditions.json" && r.URL.Query().Get("policy_id") != "10" {
+			body = `{"synthetics_conditions": []}`
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newrelic.New(
+		nr.ConfigAdminAPIKey("key"),
+		nr.ConfigBaseURL(server.URL),
+		nr.ConfigSyntheticsBaseURL(server.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestExport(t *testing.T) {
+	out := &bytes.Buffer{}
+	warnings := &bytes.Buffer{}
+
+	err := Export(newTestClient(t), out, Options{Namespace: "monitoring", AccountRef: "production", Warnings: warnings})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents := strings.Split(out.String(), "---\n")
+	if len(documents) != 6 {
+		t.Fatalf("expected 6 resources, got %d:\n%s", len(documents), out.String())
+	}
+
+	if !strings.Contains(warnings.String(), "campfire") {
+		t.Fatalf("expected a warning for the unsupported channel, got %q", warnings.String())
+	}
+	if strings.Contains(out.String(), "hooks.slack.com") {
+		t.Fatalf("expected no credentials in the resources, got\n%s", out.String())
+	}
+
+	channel := &v1alpha1.AlertChannel{}
+	if err := yaml.UnmarshalStrict([]byte(documents[0]), channel); err != nil {
+		t.Fatal(err)
+	}
+	if channel.Spec.Slack == nil || channel.Spec.Slack.Channel != "#alerts" || channel.Spec.Configuration != nil {
+		t.Fatalf("expected the typed slack configuration, got %+v", channel.Spec)
+	}
+	url := channel.Spec.Slack.URL.ValueFrom
+	if url == nil || url.SecretKeyRef.Name != "slack" || url.SecretKeyRef.Key != "url" {
+		t.Fatalf("expected the url to be read from the Secret slack, got %+v", channel.Spec.Slack.URL)
+	}
+	if !strings.Contains(warnings.String(), "Secret slack") {
+		t.Fatalf("expected a warning naming the Secret to create, got %q", warnings.String())
+	}
+
+	renamed := &v1alpha1.AlertPolicy{}
+	if err := yaml.UnmarshalStrict([]byte(documents[1]), renamed); err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "hand-made-policy" || renamed.Spec.Name != "Hand Made Policy" {
+		t.Fatalf("expected the invalid name to be sanitized and kept in the spec, got %s %q", renamed.Name, renamed.Spec.Name)
+	}
+
+	policy := &v1alpha1.AlertPolicy{}
+	if err := yaml.UnmarshalStrict([]byte(documents[2]), policy); err != nil {
+		t.Fatal(err)
+	}
+	if policy.Kind != "AlertPolicy" || policy.Name != "production" || policy.Namespace != "monitoring" {
+		t.Fatalf("unexpected policy %+v", policy.ObjectMeta)
+	}
+	if *policy.Spec.ImportID != "10" || len(policy.Spec.Channels) != 1 || policy.Spec.Channels[0] != "slack" {
+		t.Fatalf("unexpected policy spec %+v", policy.Spec)
+	}
+	if policy.Spec.Name != "" {
+		t.Fatalf("expected no name in the spec of a valid name, got %q", policy.Spec.Name)
+	}
+	if policy.Spec.AccountRef == nil || policy.Spec.AccountRef.Name != "production" {
+		t.Fatalf("expected account ref, got %+v", policy.Spec.AccountRef)
+	}
+
+	dashboard := &v1alpha1.Dashboard{}
+	if err := yaml.UnmarshalStrict([]byte(documents[3]), dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboard.Spec.Widgets) != 1 || dashboard.Spec.Widgets[0].NRQL != "SELECT count(*) FROM Transaction" {
+		t.Fatalf("unexpected dashboard widgets %+v", dashboard.Spec.Widgets)
+	}
+
+	scripted := &v1alpha1.Monitor{}
+	if err := yaml.UnmarshalStrict([]byte(documents[4]), scripted); err != nil {
+		t.Fatal(err)
+	}
+	if scripted.Name != "login" || scripted.Spec.Script == nil || *scripted.Spec.Script.ScriptText != "$browser.get('https://example.com/login')" {
+		t.Fatalf("expected the script of the scripted monitor, got %+v", scripted.Spec.Script)
+	}
+
+	monitor := &v1alpha1.Monitor{}
+	if err := yaml.UnmarshalStrict([]byte(documents[5]), monitor); err != nil {
+		t.Fatal(err)
+	}
+	if *monitor.Spec.ImportID != "abc-123" || *monitor.Spec.Status != v1alpha1.Enabled || *monitor.Spec.Frequency != 5 {
+		t.Fatalf("unexpected monitor spec %+v", monitor.Spec)
+	}
+	if len(monitor.Spec.Conditions) != 1 || monitor.Spec.Conditions[0].PolicyName != "production" {
+		t.Fatalf("expected monitor condition on production, got %+v", monitor.Spec.Conditions)
+	}
+}
+
+func TestExportNameFilter(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	err := Export(newTestClient(t), out, Options{NameFilter: regexp.MustCompile("^web")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := &v1alpha1.Monitor{}
+	if err := yaml.UnmarshalStrict(out.Bytes(), monitor); err != nil {
+		t.Fatal(err)
+	}
+	if monitor.Kind != "Monitor" || monitor.Name != "website" {
+		t.Fatalf("expected only the website monitor, got\n%s", out.String())
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	e := &exporter{names: map[string]bool{}}
+
+	for _, c := range []struct {
+		kind, name, id, expected string
+	}{
+		{"Monitor", "website", "1", "website"},
+		{"Monitor", "website", "2", "website-2"},
+		{"Dashboard", "website", "3", "website"},
+		{"Monitor", "API / Health.check", "4", "api-health-check"},
+		{"Monitor", "監視", "5", "monitor-5"},
+	} {
+		name, ok := e.resourceName(c.kind, c.name, c.id)
+		if !ok || name != c.expected {
+			t.Fatalf("expected %s %q to be written as %s, got %s", c.kind, c.name, c.expected, name)
+		}
+	}
+}
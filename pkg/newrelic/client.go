@@ -0,0 +1,164 @@
+// Package newrelic contains the subset of the New Relic API used by the operator
+package newrelic
+
+import (
+	"os"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+// Alerts are the calls made against the New Relic Alerts API
+type Alerts interface {
+	ListPolicies(params *alerts.ListPoliciesParams) ([]alerts.Policy, error)
+	GetPolicy(id int) (*alerts.Policy, error)
+	CreatePolicy(policy alerts.Policy) (*alerts.Policy, error)
+	UpdatePolicy(policy alerts.Policy) (*alerts.Policy, error)
+	DeletePolicy(id int) (*alerts.Policy, error)
+
+	ListChannels() ([]*alerts.Channel, error)
+	GetChannel(id int) (*alerts.Channel, error)
+	CreateChannel(channel alerts.Channel) (*alerts.Channel, error)
+	DeleteChannel(id int) (*alerts.Channel, error)
+	UpdatePolicyChannels(policyID int, channelIDs []int) (*alerts.PolicyChannels, error)
+	DeletePolicyChannel(policyID int, channelID int) (*alerts.Channel, error)
+
+	ListSyntheticsConditions(policyID int) ([]*alerts.SyntheticsCondition, error)
+	CreateSyntheticsCondition(policyID int, condition alerts.SyntheticsCondition) (*alerts.SyntheticsCondition, error)
+	UpdateSyntheticsCondition(condition alerts.SyntheticsCondition) (*alerts.SyntheticsCondition, error)
+	DeleteSyntheticsCondition(conditionID int) (*alerts.SyntheticsCondition, error)
+
+	ListNrqlConditions(policyID int) ([]*alerts.NrqlCondition, error)
+	GetNrqlCondition(policyID int, id int) (*alerts.NrqlCondition, error)
+	CreateNrqlCondition(policyID int, condition alerts.NrqlCondition) (*alerts.NrqlCondition, error)
+	UpdateNrqlCondition(condition alerts.NrqlCondition) (*alerts.NrqlCondition, error)
+	DeleteNrqlCondition(id int) (*alerts.NrqlCondition, error)
+
+	ListConditions(policyID int) ([]*alerts.Condition, error)
+	GetCondition(policyID int, id int) (*alerts.Condition, error)
+	CreateCondition(policyID int, condition alerts.Condition) (*alerts.Condition, error)
+	UpdateCondition(condition alerts.Condition) (*alerts.Condition, error)
+	DeleteCondition(id int) (*alerts.Condition, error)
+
+	ListInfrastructureConditions(policyID int) ([]alerts.InfrastructureCondition, error)
+	GetInfrastructureCondition(conditionID int) (*alerts.InfrastructureCondition, error)
+	CreateInfrastructureCondition(condition alerts.InfrastructureCondition) (*alerts.InfrastructureCondition, error)
+	UpdateInfrastructureCondition(condition alerts.InfrastructureCondition) (*alerts.InfrastructureCondition, error)
+	DeleteInfrastructureCondition(conditionID int) error
+}
+
+// APM are the calls made against the New Relic APM API
+type APM interface {
+	ListApplications(params *apm.ListApplicationsParams) ([]*apm.Application, error)
+}
+
+// Dashboards are the calls made against the New Relic Dashboards API
+type Dashboards interface {
+	ListDashboards(params *dashboards.ListDashboardsParams) ([]*dashboards.Dashboard, error)
+	GetDashboard(dashboardID int) (*dashboards.Dashboard, error)
+	CreateDashboard(dashboard dashboards.Dashboard) (*dashboards.Dashboard, error)
+	UpdateDashboard(dashboard dashboards.Dashboard) (*dashboards.Dashboard, error)
+	DeleteDashboard(dashboardID int) (*dashboards.Dashboard, error)
+}
+
+// Synthetics are the calls made against the New Relic Synthetics API
+type Synthetics interface {
+	ListMonitors() ([]*synthetics.Monitor, error)
+	GetMonitor(monitorID string) (*synthetics.Monitor, error)
+	CreateMonitor(monitor synthetics.Monitor) (*synthetics.Monitor, error)
+	UpdateMonitor(monitor synthetics.Monitor) (*synthetics.Monitor, error)
+	DeleteMonitor(monitorID string) error
+	GetMonitorScript(monitorID string) (*synthetics.MonitorScript, error)
+	UpdateMonitorScript(monitorID string, script synthetics.MonitorScript) (*synthetics.MonitorScript, error)
+	GetSecureCredential(key string) (*synthetics.SecureCredential, error)
+	AddSecureCredential(key, value, description string) (*synthetics.SecureCredential, error)
+	UpdateSecureCredential(key, value, description string) (*synthetics.SecureCredential, error)
+	DeleteSecureCredential(key string) error
+	ListLocations() ([]*Location, error)
+}
+
+// Client is a collection of the New Relic APIs used by the operator
+type Client struct {
+	// AccountID is the account the client was configured for, it is 0 when unknown
+	AccountID int
+
+	Alerts     Alerts
+	APM        APM
+	Dashboards Dashboards
+	Synthetics Synthetics
+
+	locations locationCache
+}
+
+// Credentials used to connect to a New Relic account
+type Credentials struct {
+	AdminAPIKey    string
+	PersonalAPIKey string
+	AccountID      int
+	Region         string
+}
+
+// New returns a client backed by the New Relic API
+func New(opts ...nr.ConfigOption) (*Client, error) {
+	c, err := nr.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the options are applied again for the calls the New Relic client is missing
+	cfg := config.New()
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Client{
+		Alerts:     &c.Alerts,
+		APM:        &c.APM,
+		Dashboards: &c.Dashboards,
+		Synthetics: newSyntheticsAPI(&c.Synthetics, cfg),
+	}, nil
+}
+
+// NewFromCredentials returns a client backed by the New Relic API for the given credentials
+func NewFromCredentials(credentials Credentials) (*Client, error) {
+	opts := []nr.ConfigOption{}
+	if credentials.AdminAPIKey != "" {
+		opts = append(opts, nr.ConfigAdminAPIKey(credentials.AdminAPIKey))
+	}
+	if credentials.PersonalAPIKey != "" {
+		opts = append(opts, nr.ConfigPersonalAPIKey(credentials.PersonalAPIKey))
+	}
+	if credentials.Region != "" {
+		name, err := region.Parse(credentials.Region)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, nr.ConfigRegion(name))
+	}
+
+	client, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client.AccountID = credentials.AccountID
+	return client, nil
+}
+
+// NewFromEnv returns a client using the API key from NEW_RELIC_APIKEY
+func NewFromEnv() (*Client, error) {
+	return New(nr.ConfigAdminAPIKey(CredentialsFromEnv().AdminAPIKey))
+}
+
+// CredentialsFromEnv returns the credentials NewFromEnv uses
+func CredentialsFromEnv() Credentials {
+	return Credentials{AdminAPIKey: os.Getenv("NEW_RELIC_APIKEY")}
+}
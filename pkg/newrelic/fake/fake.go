@@ -0,0 +1,966 @@
+// Package fake provides an in-memory New Relic account for testing
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+var (
+	_ newrelic.Alerts     = &Account{}
+	_ newrelic.APM        = &Account{}
+	_ newrelic.Dashboards = &Account{}
+	_ newrelic.Synthetics = &Account{}
+)
+
+// Account is an in-memory New Relic account that mimics the behaviour of the API
+type Account struct {
+	mu     sync.Mutex
+	lastID int
+
+	policies             map[int]alerts.Policy
+	channels             map[int]alerts.Channel
+	syntheticsConditions map[int]syntheticsCondition
+	nrqlConditions       map[int]nrqlCondition
+	conditions           map[int]condition
+	infraConditions      map[int]alerts.InfrastructureCondition
+	applications         map[int]apm.Application
+	dashboards           map[int]dashboards.Dashboard
+	monitors             map[string]synthetics.Monitor
+	scripts              map[string]synthetics.MonitorScript
+	secureCredentials    map[string]synthetics.SecureCredential
+	locations            []*newrelic.Location
+}
+
+type syntheticsCondition struct {
+	policyID  int
+	condition alerts.SyntheticsCondition
+}
+
+type nrqlCondition struct {
+	policyID  int
+	condition alerts.NrqlCondition
+}
+
+type condition struct {
+	policyID  int
+	condition alerts.Condition
+}
+
+// NewAccount returns an empty account
+func NewAccount() *Account {
+	return &Account{
+		policies:             map[int]alerts.Policy{},
+		channels:             map[int]alerts.Channel{},
+		syntheticsConditions: map[int]syntheticsCondition{},
+		nrqlConditions:       map[int]nrqlCondition{},
+		conditions:           map[int]condition{},
+		infraConditions:      map[int]alerts.InfrastructureCondition{},
+		applications:         map[int]apm.Application{},
+		dashboards:           map[int]dashboards.Dashboard{},
+		monitors:             map[string]synthetics.Monitor{},
+		scripts:              map[string]synthetics.MonitorScript{},
+		secureCredentials:    map[string]synthetics.SecureCredential{},
+		locations: []*newrelic.Location{
+			{Name: "AWS_EU_WEST_1", Label: "Dublin, IE"},
+			{Name: "AWS_US_EAST_1", Label: "Washington, DC, USA"},
+			{Name: "AWS_US_WEST_1", Label: "San Francisco, CA, USA"},
+		},
+	}
+}
+
+// New returns a client backed by an empty in-memory account
+func New() *newrelic.Client {
+	return NewAccount().Client()
+}
+
+// Client returns a client backed by this account
+func (a *Account) Client() *newrelic.Client {
+	return &newrelic.Client{
+		Alerts:     a,
+		APM:        a,
+		Dashboards: a,
+		Synthetics: a,
+	}
+}
+
+func (a *Account) nextID() int {
+	a.lastID++
+	return a.lastID
+}
+
+// ListPolicies returns the policies whose name contains params.Name
+func (a *Account) ListPolicies(params *alerts.ListPoliciesParams) ([]alerts.Policy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []alerts.Policy{}
+	for _, id := range sortedIDs(a.policies) {
+		item := a.policies[id]
+		if params != nil && !strings.Contains(strings.ToLower(item.Name), strings.ToLower(params.Name)) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// GetPolicy returns a policy by ID
+func (a *Account) GetPolicy(id int) (*alerts.Policy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.policies[id]
+	if !ok {
+		return nil, nrErrors.NewNotFoundf("no alert policy found for id %d", id)
+	}
+	return &item, nil
+}
+
+// CreatePolicy stores a new policy
+func (a *Account) CreatePolicy(policy alerts.Policy) (*alerts.Policy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if policy.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if policy.IncidentPreference == "" {
+		policy.IncidentPreference = alerts.IncidentPreferenceTypes.PerPolicy
+	}
+
+	policy.ID = a.nextID()
+	a.policies[policy.ID] = policy
+	return &policy, nil
+}
+
+// UpdatePolicy replaces an existing policy
+func (a *Account) UpdatePolicy(policy alerts.Policy) (*alerts.Policy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current, ok := a.policies[policy.ID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if policy.IncidentPreference == "" {
+		policy.IncidentPreference = current.IncidentPreference
+	}
+
+	a.policies[policy.ID] = policy
+	return &policy, nil
+}
+
+// DeletePolicy removes a policy along with its conditions and channel links
+func (a *Account) DeletePolicy(id int) (*alerts.Policy, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.policies[id]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.policies, id)
+	for conditionID, condition := range a.syntheticsConditions {
+		if condition.policyID == id {
+			delete(a.syntheticsConditions, conditionID)
+		}
+	}
+	for conditionID, condition := range a.nrqlConditions {
+		if condition.policyID == id {
+			delete(a.nrqlConditions, conditionID)
+		}
+	}
+	for conditionID, condition := range a.conditions {
+		if condition.policyID == id {
+			delete(a.conditions, conditionID)
+		}
+	}
+	for conditionID, condition := range a.infraConditions {
+		if condition.PolicyID == id {
+			delete(a.infraConditions, conditionID)
+		}
+	}
+	for channelID, channel := range a.channels {
+		channel.Links.PolicyIDs = removeInt(channel.Links.PolicyIDs, id)
+		a.channels[channelID] = channel
+	}
+	return &item, nil
+}
+
+// ListChannels returns all channels
+func (a *Account) ListChannels() ([]*alerts.Channel, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*alerts.Channel{}
+	for _, id := range sortedIDs(a.channels) {
+		item := a.channels[id]
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
+// GetChannel returns a channel by ID
+func (a *Account) GetChannel(id int) (*alerts.Channel, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.channels[id]
+	if !ok {
+		return nil, nrErrors.NewNotFoundf("no channel found for id %d", id)
+	}
+	return &item, nil
+}
+
+// CreateChannel stores a new channel
+func (a *Account) CreateChannel(channel alerts.Channel) (*alerts.Channel, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if channel.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if channel.Type == "" {
+		return nil, errors.New("type is required")
+	}
+
+	channel.ID = a.nextID()
+	channel.Links = alerts.ChannelLinks{}
+	a.channels[channel.ID] = channel
+	return &channel, nil
+}
+
+// DeleteChannel removes a channel
+func (a *Account) DeleteChannel(id int) (*alerts.Channel, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.channels[id]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.channels, id)
+	return &item, nil
+}
+
+// UpdatePolicyChannels adds the channels to the policy, existing links are kept
+func (a *Account) UpdatePolicyChannels(policyID int, channelIDs []int) (*alerts.PolicyChannels, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.policies[policyID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	for _, id := range channelIDs {
+		if _, ok := a.channels[id]; !ok {
+			return nil, fmt.Errorf("channel %d does not exist", id)
+		}
+	}
+
+	for _, id := range channelIDs {
+		channel := a.channels[id]
+		channel.Links.PolicyIDs = append(removeInt(channel.Links.PolicyIDs, policyID), policyID)
+		a.channels[id] = channel
+	}
+
+	result := &alerts.PolicyChannels{ID: policyID}
+	for _, id := range sortedIDs(a.channels) {
+		if containsInt(a.channels[id].Links.PolicyIDs, policyID) {
+			result.ChannelIDs = append(result.ChannelIDs, id)
+		}
+	}
+	return result, nil
+}
+
+// DeletePolicyChannel removes the link between a policy and a channel
+func (a *Account) DeletePolicyChannel(policyID int, channelID int) (*alerts.Channel, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	channel, ok := a.channels[channelID]
+	if !ok || !containsInt(channel.Links.PolicyIDs, policyID) {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	channel.Links.PolicyIDs = removeInt(channel.Links.PolicyIDs, policyID)
+	a.channels[channelID] = channel
+	return &channel, nil
+}
+
+// ListSyntheticsConditions returns the synthetics conditions of a policy
+func (a *Account) ListSyntheticsConditions(policyID int) ([]*alerts.SyntheticsCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*alerts.SyntheticsCondition{}
+	for _, id := range sortedIDs(a.syntheticsConditions) {
+		item := a.syntheticsConditions[id]
+		if item.policyID == policyID {
+			condition := item.condition
+			result = append(result, &condition)
+		}
+	}
+	return result, nil
+}
+
+// CreateSyntheticsCondition stores a new synthetics condition on a policy
+func (a *Account) CreateSyntheticsCondition(policyID int, condition alerts.SyntheticsCondition) (*alerts.SyntheticsCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.policies[policyID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if _, ok := a.monitors[condition.MonitorID]; !ok {
+		return nil, fmt.Errorf("monitor %s does not exist", condition.MonitorID)
+	}
+
+	condition.ID = a.nextID()
+	a.syntheticsConditions[condition.ID] = syntheticsCondition{policyID: policyID, condition: condition}
+	return &condition, nil
+}
+
+// UpdateSyntheticsCondition replaces an existing synthetics condition
+func (a *Account) UpdateSyntheticsCondition(condition alerts.SyntheticsCondition) (*alerts.SyntheticsCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.syntheticsConditions[condition.ID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	item.condition = condition
+	a.syntheticsConditions[condition.ID] = item
+	return &condition, nil
+}
+
+// DeleteSyntheticsCondition removes a synthetics condition
+func (a *Account) DeleteSyntheticsCondition(conditionID int) (*alerts.SyntheticsCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.syntheticsConditions[conditionID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.syntheticsConditions, conditionID)
+	return &item.condition, nil
+}
+
+// ListNrqlConditions returns the NRQL conditions of a policy
+func (a *Account) ListNrqlConditions(policyID int) ([]*alerts.NrqlCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*alerts.NrqlCondition{}
+	for _, id := range sortedIDs(a.nrqlConditions) {
+		item := a.nrqlConditions[id]
+		if item.policyID == policyID {
+			condition := item.condition
+			result = append(result, &condition)
+		}
+	}
+	return result, nil
+}
+
+// GetNrqlCondition returns a NRQL condition of a policy by ID
+func (a *Account) GetNrqlCondition(policyID int, id int) (*alerts.NrqlCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.nrqlConditions[id]
+	if !ok || item.policyID != policyID {
+		return nil, nrErrors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
+	}
+	return &item.condition, nil
+}
+
+// CreateNrqlCondition stores a new NRQL condition on a policy
+func (a *Account) CreateNrqlCondition(policyID int, condition alerts.NrqlCondition) (*alerts.NrqlCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.policies[policyID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if err := validateNrqlCondition(condition); err != nil {
+		return nil, err
+	}
+
+	condition.ID = a.nextID()
+	a.nrqlConditions[condition.ID] = nrqlCondition{policyID: policyID, condition: condition}
+	return &condition, nil
+}
+
+// UpdateNrqlCondition replaces an existing NRQL condition
+func (a *Account) UpdateNrqlCondition(condition alerts.NrqlCondition) (*alerts.NrqlCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.nrqlConditions[condition.ID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if err := validateNrqlCondition(condition); err != nil {
+		return nil, err
+	}
+
+	item.condition = condition
+	a.nrqlConditions[condition.ID] = item
+	return &condition, nil
+}
+
+// DeleteNrqlCondition removes a NRQL condition
+func (a *Account) DeleteNrqlCondition(id int) (*alerts.NrqlCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.nrqlConditions[id]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.nrqlConditions, id)
+	return &item.condition, nil
+}
+
+func validateNrqlCondition(condition alerts.NrqlCondition) error {
+	if condition.Name == "" {
+		return errors.New("name is required")
+	}
+	if condition.Nrql.Query == "" {
+		return errors.New("query is required")
+	}
+	if len(condition.Terms) == 0 {
+		return errors.New("at least one term is required")
+	}
+	return nil
+}
+
+// ListConditions returns the APM, browser and mobile conditions of a policy
+func (a *Account) ListConditions(policyID int) ([]*alerts.Condition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*alerts.Condition{}
+	for _, id := range sortedIDs(a.conditions) {
+		item := a.conditions[id]
+		if item.policyID == policyID {
+			condition := item.condition
+			result = append(result, &condition)
+		}
+	}
+	return result, nil
+}
+
+// GetCondition returns a condition of a policy by ID
+func (a *Account) GetCondition(policyID int, id int) (*alerts.Condition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.conditions[id]
+	if !ok || item.policyID != policyID {
+		return nil, nrErrors.NewNotFoundf("no condition found for policy %d and condition ID %d", policyID, id)
+	}
+	return &item.condition, nil
+}
+
+// CreateCondition stores a new condition on a policy
+func (a *Account) CreateCondition(policyID int, data alerts.Condition) (*alerts.Condition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.policies[policyID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if err := a.validateCondition(data); err != nil {
+		return nil, err
+	}
+
+	data.ID = a.nextID()
+	a.conditions[data.ID] = condition{policyID: policyID, condition: data}
+	return &data, nil
+}
+
+// UpdateCondition replaces an existing condition
+func (a *Account) UpdateCondition(data alerts.Condition) (*alerts.Condition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.conditions[data.ID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if err := a.validateCondition(data); err != nil {
+		return nil, err
+	}
+
+	item.condition = data
+	a.conditions[data.ID] = item
+	return &data, nil
+}
+
+// DeleteCondition removes a condition
+func (a *Account) DeleteCondition(id int) (*alerts.Condition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.conditions[id]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.conditions, id)
+	return &item.condition, nil
+}
+
+func (a *Account) validateCondition(data alerts.Condition) error {
+	if data.Name == "" {
+		return errors.New("name is required")
+	}
+	if data.Type == "" || data.Metric == "" {
+		return errors.New("type and metric are required")
+	}
+	if len(data.Terms) == 0 {
+		return errors.New("at least one term is required")
+	}
+	for _, entity := range data.Entities {
+		id, err := strconv.Atoi(entity)
+		if err != nil {
+			return fmt.Errorf("entity %s is not an ID", entity)
+		}
+		if _, ok := a.applications[id]; !ok {
+			return fmt.Errorf("application %d does not exist", id)
+		}
+	}
+	return nil
+}
+
+// ListInfrastructureConditions returns the infrastructure conditions of a policy
+func (a *Account) ListInfrastructureConditions(policyID int) ([]alerts.InfrastructureCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []alerts.InfrastructureCondition{}
+	for _, id := range sortedIDs(a.infraConditions) {
+		if item := a.infraConditions[id]; item.PolicyID == policyID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// GetInfrastructureCondition returns an infrastructure condition by ID
+func (a *Account) GetInfrastructureCondition(conditionID int) (*alerts.InfrastructureCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.infraConditions[conditionID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	return &item, nil
+}
+
+// CreateInfrastructureCondition stores a new infrastructure condition on the policy it names
+func (a *Account) CreateInfrastructureCondition(data alerts.InfrastructureCondition) (*alerts.InfrastructureCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.policies[data.PolicyID]; !ok {
+		return nil, fmt.Errorf("policy %d does not exist", data.PolicyID)
+	}
+	if data.Name == "" || data.Type == "" {
+		return nil, errors.New("name and type are required")
+	}
+	if data.Critical == nil {
+		return nil, errors.New("critical threshold is required")
+	}
+
+	data.ID = a.nextID()
+	a.infraConditions[data.ID] = data
+	return &data, nil
+}
+
+// UpdateInfrastructureCondition replaces an existing infrastructure condition
+func (a *Account) UpdateInfrastructureCondition(data alerts.InfrastructureCondition) (*alerts.InfrastructureCondition, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current, ok := a.infraConditions[data.ID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if data.PolicyID != current.PolicyID {
+		return nil, errors.New("policy_id can not be changed")
+	}
+
+	a.infraConditions[data.ID] = data
+	return &data, nil
+}
+
+// DeleteInfrastructureCondition removes an infrastructure condition
+func (a *Account) DeleteInfrastructureCondition(conditionID int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.infraConditions[conditionID]; !ok {
+		return &nrErrors.NotFound{}
+	}
+
+	delete(a.infraConditions, conditionID)
+	return nil
+}
+
+// AddApplication reports a new APM application, applications are only created by agents in New Relic
+func (a *Account) AddApplication(name string) *apm.Application {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item := apm.Application{ID: a.nextID(), Name: name, Reporting: true}
+	a.applications[item.ID] = item
+	return &item
+}
+
+// ListApplications returns the applications whose name contains params.Name
+func (a *Account) ListApplications(params *apm.ListApplicationsParams) ([]*apm.Application, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*apm.Application{}
+	for _, id := range sortedIDs(a.applications) {
+		item := a.applications[id]
+		if params != nil && !strings.Contains(strings.ToLower(item.Name), strings.ToLower(params.Name)) {
+			continue
+		}
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
+// ListDashboards returns the dashboards whose title contains params.Title
+func (a *Account) ListDashboards(params *dashboards.ListDashboardsParams) ([]*dashboards.Dashboard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := []*dashboards.Dashboard{}
+	for _, id := range sortedIDs(a.dashboards) {
+		item := a.dashboards[id]
+		if params != nil && !strings.Contains(strings.ToLower(item.Title), strings.ToLower(params.Title)) {
+			continue
+		}
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
+// GetDashboard returns a dashboard by ID
+func (a *Account) GetDashboard(dashboardID int) (*dashboards.Dashboard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.dashboards[dashboardID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	return &item, nil
+}
+
+// CreateDashboard stores a new dashboard
+func (a *Account) CreateDashboard(dashboard dashboards.Dashboard) (*dashboards.Dashboard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if dashboard.Title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	dashboard.ID = a.nextID()
+	a.dashboards[dashboard.ID] = dashboard
+	return &dashboard, nil
+}
+
+// UpdateDashboard replaces an existing dashboard
+func (a *Account) UpdateDashboard(dashboard dashboards.Dashboard) (*dashboards.Dashboard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.dashboards[dashboard.ID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	a.dashboards[dashboard.ID] = dashboard
+	return &dashboard, nil
+}
+
+// DeleteDashboard removes a dashboard
+func (a *Account) DeleteDashboard(dashboardID int) (*dashboards.Dashboard, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.dashboards[dashboardID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	delete(a.dashboards, dashboardID)
+	return &item, nil
+}
+
+// ListMonitors returns all synthetics monitors
+func (a *Account) ListMonitors() ([]*synthetics.Monitor, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ids := []string{}
+	for id := range a.monitors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := []*synthetics.Monitor{}
+	for _, id := range ids {
+		item := a.monitors[id]
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
+// GetMonitor returns a synthetics monitor by ID
+func (a *Account) GetMonitor(monitorID string) (*synthetics.Monitor, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	item, ok := a.monitors[monitorID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	return &item, nil
+}
+
+// CreateMonitor stores a new synthetics monitor
+func (a *Account) CreateMonitor(monitor synthetics.Monitor) (*synthetics.Monitor, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if monitor.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(monitor.Locations) == 0 {
+		return nil, errors.New("at least one location is required")
+	}
+
+	monitor.ID = string(uuid.NewUUID())
+	a.monitors[monitor.ID] = monitor
+	return &monitor, nil
+}
+
+// UpdateMonitor replaces an existing synthetics monitor
+func (a *Account) UpdateMonitor(monitor synthetics.Monitor) (*synthetics.Monitor, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.monitors[monitor.ID]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+
+	a.monitors[monitor.ID] = monitor
+	return &monitor, nil
+}
+
+// DeleteMonitor removes a synthetics monitor and its script
+func (a *Account) DeleteMonitor(monitorID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.monitors[monitorID]; !ok {
+		return &nrErrors.NotFound{}
+	}
+
+	delete(a.monitors, monitorID)
+	delete(a.scripts, monitorID)
+	return nil
+}
+
+// GetMonitorScript returns the script of a scripted monitor
+func (a *Account) GetMonitorScript(monitorID string) (*synthetics.MonitorScript, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	script, ok := a.scripts[monitorID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	return &script, nil
+}
+
+// UpdateMonitorScript stores the script of a scripted monitor
+func (a *Account) UpdateMonitorScript(monitorID string, script synthetics.MonitorScript) (*synthetics.MonitorScript, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	monitor, ok := a.monitors[monitorID]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	if monitor.Type != synthetics.MonitorTypes.APITest && monitor.Type != synthetics.MonitorTypes.ScriptedBrowser {
+		return nil, fmt.Errorf("monitor %s of type %s does not support scripts", monitorID, monitor.Type)
+	}
+
+	a.scripts[monitorID] = script
+	return &script, nil
+}
+
+// GetSecureCredential returns a secure credential, like the API it leaves out the value
+func (a *Account) GetSecureCredential(key string) (*synthetics.SecureCredential, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	credential, ok := a.secureCredentials[key]
+	if !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	credential.Value = ""
+	return &credential, nil
+}
+
+// AddSecureCredential stores a secure credential, keys have to be unique
+func (a *Account) AddSecureCredential(key, value, description string) (*synthetics.SecureCredential, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.secureCredentials[key]; ok {
+		return nil, fmt.Errorf("secure credential %s already exists", key)
+	}
+	a.secureCredentials[key] = synthetics.SecureCredential{Key: key, Value: value, Description: description}
+	return &synthetics.SecureCredential{Key: key, Description: description}, nil
+}
+
+// UpdateSecureCredential replaces the value and description of a secure credential
+func (a *Account) UpdateSecureCredential(key, value, description string) (*synthetics.SecureCredential, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.secureCredentials[key]; !ok {
+		return nil, &nrErrors.NotFound{}
+	}
+	a.secureCredentials[key] = synthetics.SecureCredential{Key: key, Value: value, Description: description}
+	return &synthetics.SecureCredential{Key: key, Description: description}, nil
+}
+
+// DeleteSecureCredential removes a secure credential
+func (a *Account) DeleteSecureCredential(key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.secureCredentials[key]; !ok {
+		return &nrErrors.NotFound{}
+	}
+	delete(a.secureCredentials, key)
+	return nil
+}
+
+// SecureCredentialValue returns the stored value of a secure credential, which the API never returns
+func (a *Account) SecureCredentialValue(key string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.secureCredentials[key].Value
+}
+
+func sortedIDs(m interface{}) []int {
+	ids := []int{}
+	switch items := m.(type) {
+	case map[int]alerts.Policy:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]alerts.Channel:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]syntheticsCondition:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]nrqlCondition:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]condition:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]alerts.InfrastructureCondition:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]apm.Application:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	case map[int]dashboards.Dashboard:
+		for id := range items {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func containsInt(items []int, value int) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeInt(items []int, value int) []int {
+	result := []int{}
+	for _, item := range items {
+		if item != value {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ListLocations returns a few public locations and the private locations added to the account
+func (a *Account) ListLocations() ([]*newrelic.Location, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	locations := []*newrelic.Location{}
+	for _, location := range a.locations {
+		copied := *location
+		locations = append(locations, &copied)
+	}
+	return locations, nil
+}
+
+// AddPrivateLocation adds a private location, New Relic names them by an ID and shows the label
+func (a *Account) AddPrivateLocation(name, label string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.locations = append(a.locations, &newrelic.Location{Name: name, Label: label, Private: true})
+}
@@ -0,0 +1,178 @@
+package fake
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+)
+
+// lists are the responses of an empty account, every other request is answered with 404 like for a missing object
+var lists = map[string]string{
+	"/alerts_policies.json":        `{"policies": []}`,
+	"/alerts_channels.json":        `{"channels": []}`,
+	"/alerts_nrql_conditions.json": `{"nrql_conditions": []}`,
+	"/alerts_conditions.json":      `{"conditions": []}`,
+}
+
+// newEmptyClient returns a client backed by the New Relic API of an empty account
+func newEmptyClient(t *testing.T) *newrelic.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := lists[r.URL.Path]
+		if !ok || r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newrelic.New(
+		nr.ConfigAdminAPIKey("key"),
+		nr.ConfigBaseURL(server.URL),
+		nr.ConfigInfrastructureBaseURL(server.URL),
+		nr.ConfigSyntheticsBaseURL(server.URL),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// TestNotFound checks the fake reports missing objects with the error type of the real client
+func TestNotFound(t *testing.T) {
+	calls := map[string]func(*newrelic.Client) error{
+		"GetPolicy": func(c *newrelic.Client) error {
+			_, err := c.Alerts.GetPolicy(1)
+			return err
+		},
+		"DeletePolicy": func(c *newrelic.Client) error {
+			_, err := c.Alerts.DeletePolicy(1)
+			return err
+		},
+		"GetChannel": func(c *newrelic.Client) error {
+			_, err := c.Alerts.GetChannel(1)
+			return err
+		},
+		"DeleteChannel": func(c *newrelic.Client) error {
+			_, err := c.Alerts.DeleteChannel(1)
+			return err
+		},
+		"GetNrqlCondition": func(c *newrelic.Client) error {
+			_, err := c.Alerts.GetNrqlCondition(1, 2)
+			return err
+		},
+		"GetCondition": func(c *newrelic.Client) error {
+			_, err := c.Alerts.GetCondition(1, 2)
+			return err
+		},
+		"GetInfrastructureCondition": func(c *newrelic.Client) error {
+			_, err := c.Alerts.GetInfrastructureCondition(1)
+			return err
+		},
+		"GetDashboard": func(c *newrelic.Client) error {
+			_, err := c.Dashboards.GetDashboard(1)
+			return err
+		},
+		"UpdateDashboard": func(c *newrelic.Client) error {
+			_, err := c.Dashboards.UpdateDashboard(dashboards.Dashboard{ID: 1, Title: "missing"})
+			return err
+		},
+		"DeleteDashboard": func(c *newrelic.Client) error {
+			_, err := c.Dashboards.DeleteDashboard(1)
+			return err
+		},
+		"GetMonitor": func(c *newrelic.Client) error {
+			_, err := c.Synthetics.GetMonitor("missing")
+			return err
+		},
+		"UpdateMonitor": func(c *newrelic.Client) error {
+			_, err := c.Synthetics.UpdateMonitor(synthetics.Monitor{ID: "missing", Name: "missing", Type: synthetics.MonitorTypes.Ping})
+			return err
+		},
+		"DeleteMonitor": func(c *newrelic.Client) error {
+			return c.Synthetics.DeleteMonitor("missing")
+		},
+		"GetSecureCredential": func(c *newrelic.Client) error {
+			_, err := c.Synthetics.GetSecureCredential("MISSING")
+			return err
+		},
+		"DeleteSecureCredential": func(c *newrelic.Client) error {
+			return c.Synthetics.DeleteSecureCredential("MISSING")
+		},
+	}
+
+	clients := map[string]*newrelic.Client{
+		"real": newEmptyClient(t),
+		"fake": NewAccount().Client(),
+	}
+	for name, call := range calls {
+		for kind, client := range clients {
+			var notFound *nrErrors.NotFound
+			if err := call(client); !errors.As(err, &notFound) {
+				t.Fatalf("%s of the %s client: expected a not found error, got %v", name, kind, err)
+			}
+		}
+	}
+}
+
+func TestIDs(t *testing.T) {
+	account := NewAccount()
+
+	policy, err := account.CreatePolicy(alerts.Policy{Name: "policy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	channel, err := account.CreateChannel(alerts.Channel{Name: "channel", Type: alerts.ChannelTypes.Email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.ID == 0 || channel.ID == 0 || policy.ID == channel.ID {
+		t.Fatalf("expected distinct IDs, got %d and %d", policy.ID, channel.ID)
+	}
+
+	monitor, err := account.CreateMonitor(synthetics.Monitor{Name: "monitor", Type: synthetics.MonitorTypes.Ping, Frequency: 10, Locations: []string{"AWS_US_WEST_1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.ID == "" {
+		t.Fatal("expected monitors to get an ID")
+	}
+	if got, err := account.GetMonitor(monitor.ID); err != nil || got.Name != "monitor" {
+		t.Fatalf("expected the created monitor, got %v %v", got, err)
+	}
+}
+
+func TestListPoliciesByName(t *testing.T) {
+	account := NewAccount()
+	for _, name := range []string{"Production", "production-db", "staging"} {
+		if _, err := account.CreatePolicy(alerts.Policy{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// like the API the name filter matches any part of the name regardless of case
+	policies, err := account.ListPolicies(&alerts.ListPoliciesParams{Name: "production"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 2 || policies[0].Name != "Production" || policies[1].Name != "production-db" {
+		t.Fatalf("unexpected policies %v", policies)
+	}
+
+	policies, err = account.ListPolicies(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 3 {
+		t.Fatalf("expected every policy without a filter, got %v", policies)
+	}
+}
@@ -0,0 +1,100 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+)
+
+// LocationsTTL is how long the synthetics locations of an account are cached
+var LocationsTTL = 10 * time.Minute
+
+// Location is a public or private location synthetics monitors run from
+type Location struct {
+	// Name is used in the locations of a monitor, private locations are named by an ID
+	Name string `json:"name"`
+	// Label is the name shown in New Relic
+	Label   string `json:"label"`
+	Private bool   `json:"private"`
+}
+
+// syntheticsAPI adds the calls missing from the New Relic client to its Synthetics API
+type syntheticsAPI struct {
+	*synthetics.Synthetics
+
+	config config.Config
+	http   *http.Client
+}
+
+func newSyntheticsAPI(api *synthetics.Synthetics, cfg config.Config) *syntheticsAPI {
+	client := &http.Client{Transport: cfg.HTTPTransport}
+	if cfg.Timeout != nil {
+		client.Timeout = *cfg.Timeout
+	}
+
+	return &syntheticsAPI{Synthetics: api, config: cfg, http: client}
+}
+
+// ListLocations returns the public locations and the private locations of the account
+func (s *syntheticsAPI) ListLocations() ([]*Location, error) {
+	req, err := http.NewRequest(http.MethodGet, s.config.Region().SyntheticsURL("/v1/locations"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", s.config.UserAgent)
+	if s.config.AdminAPIKey != "" {
+		req.Header.Set("X-Api-Key", s.config.AdminAPIKey)
+	} else {
+		req.Header.Set("Api-Key", s.config.PersonalAPIKey)
+		req.Header.Set("Auth-Type", "User-Api-Key")
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing synthetics locations failed with status %d", resp.StatusCode)
+	}
+
+	locations := []*Location{}
+	if err := json.NewDecoder(resp.Body).Decode(&locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// locationCache keeps the locations of an account for LocationsTTL
+type locationCache struct {
+	sync.Mutex
+
+	locations []*Location
+	expires   time.Time
+}
+
+// Locations returns the synthetics locations of the account, they are only listed again once LocationsTTL passed
+func (c *Client) Locations() ([]*Location, error) {
+	c.locations.Lock()
+	defer c.locations.Unlock()
+
+	if c.locations.locations != nil && time.Now().Before(c.locations.expires) {
+		return c.locations.locations, nil
+	}
+
+	locations, err := c.Synthetics.ListLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	c.locations.locations = locations
+	c.locations.expires = time.Now().Add(LocationsTTL)
+	return locations, nil
+}
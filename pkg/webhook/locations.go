@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sstarcher/newrelic-operator/pkg/account"
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// locationValidator rejects Monitors running from locations their account does not have
+type locationValidator struct {
+	// clientFor returns the New Relic client of the account of a Monitor
+	clientFor func(context.Context, account.Object) (*newrelic.Client, error)
+	decoder   *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &locationValidator{}
+
+// InjectDecoder is called by the webhook server
+func (v *locationValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle checks the locations of created and updated Monitors
+func (v *locationValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	monitor := &v1alpha1.Monitor{}
+	if err := v.decoder.Decode(req, monitor); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if monitor.Namespace == "" {
+		monitor.Namespace = req.Namespace
+	}
+
+	var old runtime.Object
+	if req.Operation == admissionv1beta1.Update {
+		previous := &v1alpha1.Monitor{}
+		if err := v.decoder.DecodeRaw(req.OldObject, previous); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		old = previous
+	}
+
+	// an account that can not be resolved yet is reported by the controller, it does not block the Monitor
+	client, err := v.clientFor(ctx, monitor)
+	if err != nil {
+		return admission.Allowed("")
+	}
+
+	if err := monitor.ValidateLocations(v1alpha1.WithClient(ctx, client), old); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"strings"
+
+	"github.com/sstarcher/newrelic-operator/pkg/account"
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Validated are the kinds served by the validating webhook, Monitors are registered on their own in AddToManager
+var Validated = []runtime.Object{
+	&v1alpha1.AlertChannel{},
+	&v1alpha1.AlertCondition{},
+	&v1alpha1.AlertPolicy{},
+	&v1alpha1.Dashboard{},
+	&v1alpha1.InfraAlertCondition{},
+	&v1alpha1.MonitorSet{},
+	&v1alpha1.NewRelicAccount{},
+	&v1alpha1.NrqlAlertCondition{},
+	&v1alpha1.SecureCredential{},
+}
+
+// AddToManager registers the webhooks with the webhook server of the Manager, they are served at
+// /validate-newrelic-shanestarcher-com-v1alpha1-<kind> and for kinds with defaults at /mutate-newrelic-shanestarcher-com-v1alpha1-<kind>.
+// Monitors are also checked against the locations of their account, which is found through accounts.
+func AddToManager(m manager.Manager, accounts *account.Resolver) error {
+	for _, obj := range Validated {
+		if err := builder.WebhookManagedBy(m).For(obj).Complete(); err != nil {
+			return err
+		}
+	}
+
+	server := m.GetWebhookServer()
+	server.Register(path("mutate", "Monitor"), admission.DefaultingWebhookFor(&v1alpha1.Monitor{}))
+	server.Register(path("validate", "Monitor"), &webhook.Admission{Handler: admission.MultiValidatingHandler(
+		admission.ValidatingWebhookFor(&v1alpha1.Monitor{}).Handler,
+		&locationValidator{clientFor: accounts.ClientFor},
+	)})
+	return nil
+}
+
+// path is the path the builder serves the webhook of kind at
+func path(prefix string, kind string) string {
+	group := strings.Replace(v1alpha1.SchemeGroupVersion.Group, ".", "-", -1)
+	return "/" + prefix + "-" + group + "-" + v1alpha1.SchemeGroupVersion.Version + "-" + strings.ToLower(kind)
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sstarcher/newrelic-operator/pkg/account"
+	"github.com/sstarcher/newrelic-operator/pkg/apis"
+	"github.com/sstarcher/newrelic-operator/pkg/apis/newrelic/v1alpha1"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic"
+	"github.com/sstarcher/newrelic-operator/pkg/newrelic/fake"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAddToManager(t *testing.T) {
+	s := newScheme(t)
+	mgr, err := manager.New(&rest.Config{Host: "https://localhost"}, manager.Options{
+		Scheme:             s,
+		MetricsBindAddress: "0",
+		MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+			return meta.NewDefaultRESTMapper(nil), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddToManager(mgr, account.NewResolver(clientfake.NewFakeClientWithScheme(s))); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"/mutate-newrelic-shanestarcher-com-v1alpha1-monitor",
+		"/validate-newrelic-shanestarcher-com-v1alpha1-monitor",
+		"/validate-newrelic-shanestarcher-com-v1alpha1-alertchannel",
+		"/validate-newrelic-shanestarcher-com-v1alpha1-securecredential",
+	}
+	for _, path := range expected {
+		_, pattern := mgr.GetWebhookServer().WebhookMux.Handler(httptest.NewRequest("POST", path, nil))
+		if pattern != path {
+			t.Fatalf("expected a webhook at %s", path)
+		}
+	}
+
+	// kinds without defaults have no mutating webhook
+	path := "/mutate-newrelic-shanestarcher-com-v1alpha1-alertchannel"
+	if _, pattern := mgr.GetWebhookServer().WebhookMux.Handler(httptest.NewRequest("POST", path, nil)); pattern == path {
+		t.Fatalf("unexpected webhook at %s", path)
+	}
+}
+
+func monitorRequest(t *testing.T, operation admissionv1beta1.Operation, locations []string, old []string) admission.Request {
+	encode := func(locations []string) runtime.RawExtension {
+		monitor := &v1alpha1.Monitor{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Monitor"},
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor"},
+		}
+		for i := range locations {
+			monitor.Spec.Locations = append(monitor.Spec.Locations, &locations[i])
+		}
+		raw, err := json.Marshal(monitor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runtime.RawExtension{Raw: raw}
+	}
+
+	req := admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+		Operation: operation,
+		Namespace: "team",
+		Object:    encode(locations),
+	}}
+	if old != nil {
+		req.OldObject = encode(old)
+	}
+	return req
+}
+
+func TestLocationValidator(t *testing.T) {
+	nr := fake.NewAccount()
+	nr.AddPrivateLocation("1234-office", "office")
+
+	validator := &locationValidator{clientFor: func(_ context.Context, obj account.Object) (*newrelic.Client, error) {
+		if obj.GetNamespace() != "team" {
+			t.Fatalf("expected the account of the namespace of the request, got %s", obj.GetNamespace())
+		}
+		return nr.Client(), nil
+	}}
+	decoder, err := admission.NewDecoder(newScheme(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validator.InjectDecoder(decoder); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		req     admission.Request
+		allowed bool
+	}{
+		{"public location", monitorRequest(t, admissionv1beta1.Create, []string{"AWS_EU_WEST_1"}, nil), true},
+		{"private location label", monitorRequest(t, admissionv1beta1.Create, []string{"office"}, nil), true},
+		{"unknown location", monitorRequest(t, admissionv1beta1.Create, []string{"AWS_MARS_1"}, nil), false},
+		{"changed to an unknown location", monitorRequest(t, admissionv1beta1.Update, []string{"AWS_MARS_1"}, []string{"AWS_EU_WEST_1"}), false},
+		{"unchanged unknown location", monitorRequest(t, admissionv1beta1.Update, []string{"AWS_MARS_1"}, []string{"AWS_MARS_1"}), true},
+		{"delete", admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Delete}}, true},
+	}
+	for _, test := range tests {
+		resp := validator.Handle(context.TODO(), test.req)
+		if resp.Allowed != test.allowed {
+			t.Fatalf("%s: expected allowed %v, got %+v", test.name, test.allowed, resp.Result)
+		}
+	}
+
+	// without an account the controller reports the problem
+	validator.clientFor = func(context.Context, account.Object) (*newrelic.Client, error) {
+		return nil, errors.New("no account")
+	}
+	if resp := validator.Handle(context.TODO(), monitorRequest(t, admissionv1beta1.Create, []string{"AWS_MARS_1"}, nil)); !resp.Allowed {
+		t.Fatalf("expected a monitor without a resolvable account to be allowed, got %+v", resp.Result)
+	}
+}